@@ -0,0 +1,13 @@
+package global
+
+import (
+	"os"
+
+	"gateway-service-watcher/structs"
+)
+
+// AllowedNetworks contains the docker networks from the comma-separated
+// ALLOWED_NETWORKS environment variable. Only containers attached to at
+// least one of them are registered. Without networks every container is
+// registered
+var AllowedNetworks = structs.SplitList(os.Getenv("ALLOWED_NETWORKS"))
@@ -0,0 +1,47 @@
+package global
+
+import (
+	"fmt"
+	"os"
+	"strings"
+
+	"gateway-service-watcher/structs"
+)
+
+// DefaultAuthPluginName is the global auth plugin used if AUTH_PLUGIN_NAME is
+// not set
+const DefaultAuthPluginName = "kong-internal-db-auth"
+
+// AuthPluginName is the name of the Kong plugin which is enabled globally to
+// authenticate requests. It is read from AUTH_PLUGIN_NAME
+var AuthPluginName = authPluginName()
+
+func authPluginName() string {
+	if name := strings.TrimSpace(os.Getenv("AUTH_PLUGIN_NAME")); name != "" {
+		return name
+	}
+	return DefaultAuthPluginName
+}
+
+// AuthPluginConfiguration returns the configuration of the global auth
+// plugin. AUTH_PLUGIN_CONFIG is used as base and accepts the same notations
+// as the plugin labels, a JSON object or key=value pairs. For the default
+// auth plugin the introspection_url is taken from INTROSPECTION_URL unless
+// AUTH_PLUGIN_CONFIG sets it
+func AuthPluginConfiguration() (structs.PluginConfig, error) {
+	config := make(structs.PluginConfig)
+	if rawConfig := strings.TrimSpace(os.Getenv("AUTH_PLUGIN_CONFIG")); rawConfig != "" {
+		if err := config.UnmarshalLabel(rawConfig); err != nil {
+			return nil, fmt.Errorf("AUTH_PLUGIN_CONFIG: %w", err)
+		}
+	}
+	if AuthPluginName != DefaultAuthPluginName {
+		return config, nil
+	}
+	if _, isSet := config["introspection_url"]; !isSet {
+		if introspectionURL := strings.TrimSpace(os.Getenv("INTROSPECTION_URL")); introspectionURL != "" {
+			config["introspection_url"] = introspectionURL
+		}
+	}
+	return config, nil
+}
@@ -0,0 +1,105 @@
+package global
+
+import (
+	"reflect"
+	"testing"
+
+	"gateway-service-watcher/structs"
+)
+
+func TestAuthPluginConfiguration(t *testing.T) {
+	tests := []struct {
+		name             string
+		config           string
+		introspectionURL string
+		expected         structs.PluginConfig
+		wantError        bool
+	}{
+		{
+			name:     "nothing set",
+			expected: structs.PluginConfig{},
+		},
+		{
+			name:             "introspection url only",
+			introspectionURL: "http://auth/introspect",
+			expected:         structs.PluginConfig{"introspection_url": "http://auth/introspect"},
+		},
+		{
+			name:             "merged with the json config",
+			config:           `{"ttl": 30, "hide_credentials": true, "scopes": ["read"]}`,
+			introspectionURL: "http://auth/introspect",
+			expected: structs.PluginConfig{
+				"ttl":               30,
+				"hide_credentials":  true,
+				"scopes":            []interface{}{"read"},
+				"introspection_url": "http://auth/introspect",
+			},
+		},
+		{
+			name:             "config overrides the introspection url",
+			config:           `{"introspection_url": "http://other/introspect"}`,
+			introspectionURL: "http://auth/introspect",
+			expected:         structs.PluginConfig{"introspection_url": "http://other/introspect"},
+		},
+		{
+			name:     "pair notation",
+			config:   "ttl=30;hide_credentials=true",
+			expected: structs.PluginConfig{"ttl": 30, "hide_credentials": true},
+		},
+		{name: "null", config: "null", wantError: true},
+		{name: "array", config: `[{"ttl": 30}]`, wantError: true},
+		{name: "invalid json", config: `{"ttl": }`, wantError: true},
+	}
+	for _, test := range tests {
+		t.Run(test.name, func(t *testing.T) {
+			t.Setenv("AUTH_PLUGIN_CONFIG", test.config)
+			t.Setenv("INTROSPECTION_URL", test.introspectionURL)
+			config, err := AuthPluginConfiguration()
+			if test.wantError {
+				if err == nil {
+					t.Errorf("expected an error, got %v", config)
+				}
+				return
+			}
+			if err != nil {
+				t.Fatalf("unexpected error: %v", err)
+			}
+			if !reflect.DeepEqual(config, test.expected) {
+				t.Errorf("expected %v, got %v", test.expected, config)
+			}
+		})
+	}
+}
+
+func TestAuthPluginConfigurationOtherPlugin(t *testing.T) {
+	previous := AuthPluginName
+	AuthPluginName = "oauth2-introspection"
+	t.Cleanup(func() { AuthPluginName = previous })
+
+	t.Setenv("AUTH_PLUGIN_CONFIG", `{"ttl": 30}`)
+	t.Setenv("INTROSPECTION_URL", "http://auth/introspect")
+	config, err := AuthPluginConfiguration()
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	if expected := (structs.PluginConfig{"ttl": 30}); !reflect.DeepEqual(config, expected) {
+		t.Errorf("expected %v without the introspection url, got %v", expected, config)
+	}
+}
+
+func TestAuthPluginName(t *testing.T) {
+	tests := []struct {
+		value    string
+		expected string
+	}{
+		{"", DefaultAuthPluginName},
+		{"  ", DefaultAuthPluginName},
+		{" oauth2-introspection ", "oauth2-introspection"},
+	}
+	for _, test := range tests {
+		t.Setenv("AUTH_PLUGIN_NAME", test.value)
+		if name := authPluginName(); name != test.expected {
+			t.Errorf("%q: expected '%s', got '%s'", test.value, test.expected, name)
+		}
+	}
+}
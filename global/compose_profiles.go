@@ -0,0 +1,13 @@
+package global
+
+import (
+	"os"
+
+	"gateway-service-watcher/structs"
+)
+
+// ComposeProfiles contains the docker compose profiles from the
+// comma-separated WATCHDOG_COMPOSE_PROFILES environment variable. Only
+// containers of these profiles are registered. Without profiles every
+// container is registered
+var ComposeProfiles = structs.SplitList(os.Getenv("WATCHDOG_COMPOSE_PROFILES"))
@@ -0,0 +1,19 @@
+package global
+
+import (
+	"os"
+	"strings"
+)
+
+// ComposeProject is read from WATCHDOG_CONTAINER_FILTER_COMPOSE_PROJECT, or
+// WATCHDOG_COMPOSE_PROJECT if the former is empty. Only containers of this
+// docker compose project are watched. Without a project the containers of
+// every project are watched
+var ComposeProject = composeProject()
+
+func composeProject() string {
+	if project := strings.TrimSpace(os.Getenv("WATCHDOG_CONTAINER_FILTER_COMPOSE_PROJECT")); project != "" {
+		return project
+	}
+	return strings.TrimSpace(os.Getenv("WATCHDOG_COMPOSE_PROJECT"))
+}
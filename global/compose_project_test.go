@@ -0,0 +1,26 @@
+package global
+
+import "testing"
+
+func TestComposeProject(t *testing.T) {
+	tests := []struct {
+		name     string
+		filter   string
+		fallback string
+		expected string
+	}{
+		{"unset", "", "", ""},
+		{"filter variable", " wisdom ", "", "wisdom"},
+		{"fallback variable", "", "monitoring", "monitoring"},
+		{"filter variable wins", "wisdom", "monitoring", "wisdom"},
+	}
+	for _, test := range tests {
+		t.Run(test.name, func(t *testing.T) {
+			t.Setenv("WATCHDOG_CONTAINER_FILTER_COMPOSE_PROJECT", test.filter)
+			t.Setenv("WATCHDOG_COMPOSE_PROJECT", test.fallback)
+			if project := composeProject(); project != test.expected {
+				t.Errorf("expected '%s', got '%s'", test.expected, project)
+			}
+		})
+	}
+}
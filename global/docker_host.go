@@ -0,0 +1,96 @@
+package global
+
+import (
+	"errors"
+	"fmt"
+	"net"
+	"os"
+	"strings"
+	"time"
+)
+
+// DefaultDockerHost is used if DOCKER_HOST is not set
+const DefaultDockerHost = "unix:///var/run/docker.sock"
+
+// DockerHost describes how the docker daemon is reached
+type DockerHost struct {
+	// Network is either "unix" or "tcp"
+	Network string
+	// Address is the socket path or the host:port of the daemon. Abstract
+	// unix sockets start with an "@"
+	Address string
+}
+
+// ParseDockerHost parses a DOCKER_HOST value. An empty value is treated as
+// DefaultDockerHost
+func ParseDockerHost(dockerHost string) (DockerHost, error) {
+	dockerHost = strings.TrimSpace(dockerHost)
+	if dockerHost == "" {
+		dockerHost = DefaultDockerHost
+	}
+	switch {
+	case strings.HasPrefix(dockerHost, "unix://"):
+		path := strings.TrimPrefix(dockerHost, "unix://")
+		if path == "" || path == "@" {
+			return DockerHost{}, fmt.Errorf("DOCKER_HOST '%s' does not contain a socket path", dockerHost)
+		}
+		return DockerHost{Network: "unix", Address: path}, nil
+	case strings.HasPrefix(dockerHost, "tcp://"):
+		address := strings.TrimSuffix(strings.TrimPrefix(dockerHost, "tcp://"), "/")
+		if _, _, err := net.SplitHostPort(address); err != nil {
+			return DockerHost{}, fmt.Errorf("DOCKER_HOST '%s' does not contain a host and port: %w", dockerHost, err)
+		}
+		return DockerHost{Network: "tcp", Address: address}, nil
+	case strings.HasPrefix(dockerHost, "fd://"):
+		return DockerHost{}, fmt.Errorf("DOCKER_HOST '%s' uses a file descriptor socket which is not supported", dockerHost)
+	default:
+		return DockerHost{}, fmt.Errorf("DOCKER_HOST '%s' uses an unsupported scheme, expected unix:// or tcp://", dockerHost)
+	}
+}
+
+// IsAbstractSocket reports whether the host is an abstract unix socket
+func (h DockerHost) IsAbstractSocket() bool {
+	return h.Network == "unix" && strings.HasPrefix(h.Address, "@")
+}
+
+// DockerTLSPort is the port the docker daemon listens on for TLS connections
+const DockerTLSPort = "2376"
+
+// CheckDockerTLS rejects docker hosts which expect TLS connections. The
+// watchdog connects to the daemon without TLS, so such hosts would only fail
+// with handshake errors while waiting for the daemon
+func CheckDockerTLS(host DockerHost) error {
+	if strings.TrimSpace(os.Getenv("DOCKER_TLS_VERIFY")) != "" {
+		return fmt.Errorf("DOCKER_TLS_VERIFY is set, but TLS connections to the docker daemon are not supported")
+	}
+	if host.Network != "tcp" {
+		return nil
+	}
+	if _, port, err := net.SplitHostPort(host.Address); err == nil && port == DockerTLSPort {
+		return fmt.Errorf("DOCKER_HOST 'tcp://%s' uses the docker TLS port %s, but TLS connections to the docker daemon are not supported", host.Address, DockerTLSPort)
+	}
+	return nil
+}
+
+// CheckDockerSocket verifies that the unix socket of the docker daemon exists
+// and that the current user may connect to it. Hosts reached via tcp and
+// abstract sockets are not checked since they have no file permissions
+func CheckDockerSocket(host DockerHost) error {
+	if host.Network != "unix" || host.IsAbstractSocket() {
+		return nil
+	}
+	if _, err := os.Stat(host.Address); err != nil {
+		if errors.Is(err, os.ErrNotExist) {
+			return fmt.Errorf("the docker socket '%s' does not exist, check that it is mounted into the container", host.Address)
+		}
+		return fmt.Errorf("unable to access the docker socket '%s': %w", host.Address, err)
+	}
+	connection, err := net.DialTimeout("unix", host.Address, time.Second)
+	if err != nil {
+		if errors.Is(err, os.ErrPermission) {
+			return fmt.Errorf("the user %d is not allowed to use the docker socket '%s', check the socket permissions or run the watchdog in the socket's group", os.Getuid(), host.Address)
+		}
+		return fmt.Errorf("unable to connect to the docker socket '%s': %w", host.Address, err)
+	}
+	return connection.Close()
+}
@@ -0,0 +1,113 @@
+package global
+
+import (
+	"net"
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+)
+
+func TestParseDockerHost(t *testing.T) {
+	tests := []struct {
+		name      string
+		value     string
+		expected  DockerHost
+		wantError bool
+	}{
+		{"default", "", DockerHost{Network: "unix", Address: "/var/run/docker.sock"}, false},
+		{"unix socket", "unix:///run/user/1000/docker.sock", DockerHost{Network: "unix", Address: "/run/user/1000/docker.sock"}, false},
+		{"abstract socket", "unix://@docker", DockerHost{Network: "unix", Address: "@docker"}, false},
+		{"empty unix socket", "unix://", DockerHost{}, true},
+		{"empty abstract socket", "unix://@", DockerHost{}, true},
+		{"tcp", "tcp://docker:2375", DockerHost{Network: "tcp", Address: "docker:2375"}, false},
+		{"tcp without port", "tcp://docker", DockerHost{}, true},
+		{"file descriptor", "fd://", DockerHost{}, true},
+		{"unknown scheme", "ssh://user@docker", DockerHost{}, true},
+	}
+	for _, test := range tests {
+		t.Run(test.name, func(t *testing.T) {
+			host, err := ParseDockerHost(test.value)
+			if test.wantError {
+				if err == nil {
+					t.Errorf("expected an error, got %+v", host)
+				}
+				return
+			}
+			if err != nil {
+				t.Fatalf("unexpected error: %v", err)
+			}
+			if host != test.expected {
+				t.Errorf("expected %+v, got %+v", test.expected, host)
+			}
+		})
+	}
+}
+
+func TestCheckDockerSocket(t *testing.T) {
+	directory := t.TempDir()
+	socketPath := filepath.Join(directory, "docker.sock")
+	listener, err := net.Listen("unix", socketPath)
+	if err != nil {
+		t.Fatalf("unable to listen on a unix socket: %v", err)
+	}
+	defer listener.Close()
+
+	if err := CheckDockerSocket(DockerHost{Network: "unix", Address: socketPath}); err != nil {
+		t.Errorf("unexpected error for a listening socket: %v", err)
+	}
+
+	err = CheckDockerSocket(DockerHost{Network: "unix", Address: filepath.Join(directory, "missing.sock")})
+	if err == nil || !strings.Contains(err.Error(), "does not exist") {
+		t.Errorf("expected an error for a missing socket, got %v", err)
+	}
+
+	for _, host := range []DockerHost{{Network: "tcp", Address: "docker:2375"}, {Network: "unix", Address: "@docker"}} {
+		if err := CheckDockerSocket(host); err != nil {
+			t.Errorf("%+v: unexpected error: %v", host, err)
+		}
+	}
+}
+
+func TestCheckDockerSocketPermission(t *testing.T) {
+	if os.Geteuid() == 0 {
+		t.Skip("root is not restricted by the socket permissions")
+	}
+	socketPath := filepath.Join(t.TempDir(), "docker.sock")
+	listener, err := net.Listen("unix", socketPath)
+	if err != nil {
+		t.Fatalf("unable to listen on a unix socket: %v", err)
+	}
+	defer listener.Close()
+	if err := os.Chmod(socketPath, 0o000); err != nil {
+		t.Fatal(err)
+	}
+
+	err = CheckDockerSocket(DockerHost{Network: "unix", Address: socketPath})
+	if err == nil || !strings.Contains(err.Error(), "is not allowed to use the docker socket") {
+		t.Errorf("expected a permission error, got %v", err)
+	}
+}
+
+func TestCheckDockerTLS(t *testing.T) {
+	tests := []struct {
+		name      string
+		host      DockerHost
+		tlsVerify string
+		wantError bool
+	}{
+		{"unix socket", DockerHost{Network: "unix", Address: "/var/run/docker.sock"}, "", false},
+		{"plain tcp", DockerHost{Network: "tcp", Address: "docker:2375"}, "", false},
+		{"tls port", DockerHost{Network: "tcp", Address: "docker:2376"}, "", true},
+		{"tls verify", DockerHost{Network: "tcp", Address: "docker:2375"}, "1", true},
+		{"tls verify with unix socket", DockerHost{Network: "unix", Address: "/var/run/docker.sock"}, "1", true},
+	}
+	for _, test := range tests {
+		t.Run(test.name, func(t *testing.T) {
+			t.Setenv("DOCKER_TLS_VERIFY", test.tlsVerify)
+			if err := CheckDockerTLS(test.host); (err != nil) != test.wantError {
+				t.Errorf("expected error %v, got %v", test.wantError, err)
+			}
+		})
+	}
+}
@@ -0,0 +1,28 @@
+package global
+
+import (
+	"fmt"
+	"os"
+	"strings"
+	"time"
+)
+
+// DefaultDockerSlowThreshold is the 95th percentile of the inspect durations
+// above which the docker api is considered slow
+const DefaultDockerSlowThreshold = 2 * time.Second
+
+// DockerSlowThreshold reads the 95th percentile of the container inspection
+// durations above which the docker api is reported as slow from
+// WATCHDOG_DOCKER_SLOW_THRESHOLD. If the variable is not set
+// DefaultDockerSlowThreshold is returned
+func DockerSlowThreshold() (time.Duration, error) {
+	value := strings.TrimSpace(os.Getenv("WATCHDOG_DOCKER_SLOW_THRESHOLD"))
+	if value == "" {
+		return DefaultDockerSlowThreshold, nil
+	}
+	threshold, err := time.ParseDuration(value)
+	if err != nil || threshold <= 0 {
+		return 0, fmt.Errorf("environment variable WATCHDOG_DOCKER_SLOW_THRESHOLD needs to be a positive duration")
+	}
+	return threshold, nil
+}
@@ -0,0 +1,26 @@
+package global
+
+import (
+	"testing"
+	"time"
+)
+
+func TestDockerSlowThreshold(t *testing.T) {
+	tests := []struct {
+		value     string
+		expected  time.Duration
+		wantError bool
+	}{
+		{"", DefaultDockerSlowThreshold, false},
+		{"750ms", 750 * time.Millisecond, false},
+		{"0s", 0, true},
+		{"2", 0, true},
+	}
+	for _, test := range tests {
+		t.Setenv("WATCHDOG_DOCKER_SLOW_THRESHOLD", test.value)
+		threshold, err := DockerSlowThreshold()
+		if (err != nil) != test.wantError || threshold != test.expected {
+			t.Errorf("%q: expected %s (error: %v), got %s (%v)", test.value, test.expected, test.wantError, threshold, err)
+		}
+	}
+}
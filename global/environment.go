@@ -0,0 +1,159 @@
+package global
+
+import (
+	"fmt"
+	"io"
+	"net/url"
+	"os"
+	"strings"
+)
+
+// OptionalVariable describes an environment variable which has a default
+type OptionalVariable struct {
+	// Name is the name of the environment variable
+	Name string
+	// Default describes the value used if the variable is not set
+	Default string
+	// Description explains what the variable configures
+	Description string
+}
+
+// RequiredVariables need to be set for the watchdog to start. The default
+// auth plugin additionally requires INTROSPECTION_URL unless
+// AUTH_PLUGIN_CONFIG contains the introspection_url
+var RequiredVariables = []string{"KONG_URL"}
+
+// OptionalVariables lists the environment variables which may be set to
+// change the behavior of the watchdog
+var OptionalVariables = []OptionalVariable{
+	{"DOCKER_HOST", DefaultDockerHost, "address of the docker daemon"},
+	{"WATCHDOG_NAMESPACE", "(none)", "namespace prepended to Kong object names"},
+	{"NAME_PREFIX", "(none)", "prefix prepended to Kong object names"},
+	{"KONG_TOKEN", "(none)", "token sent to the Kong admin API"},
+	{"WATCHDOG_CONTAINER_FILTER_COMPOSE_PROJECT", "(none)", "docker compose project whose containers are watched"},
+	{"AUTH_PLUGIN_NAME", DefaultAuthPluginName, "globally enabled auth plugin"},
+	{"AUTH_PLUGIN_CONFIG", "(none)", "json configuration of the auth plugin"},
+	{"WATCHDOG_COMPOSE_PROFILES", "(all)", "compose profiles of the containers which are registered"},
+	{"WATCHDOG_KONG_ADMIN_PATH_PREFIX", "(none)", "path prefix of a proxied Kong admin api"},
+	{"KONG_WAIT_TIMEOUT", DefaultWaitTimeout.String(), "time to wait for the Kong admin api at startup"},
+	{"DOCKER_WAIT_TIMEOUT", DefaultWaitTimeout.String(), "time to wait for the docker daemon at startup"},
+	{"ALLOWED_NETWORKS", "(all)", "docker networks a container needs to be attached to for the registration"},
+	{"DEFAULT_STRIP_PATH", "(kong default)", "strip_path of routes without the strip-path label"},
+	{"DEFAULT_PRESERVE_HOST", "(kong default)", "preserve_host of routes without the preserve-host label"},
+	{"DEFAULT_PROTOCOLS", "(kong default)", "protocols of routes without the protocols label"},
+	{"DEFAULT_TAGS", "(none)", "tags of routes without the tags label"},
+	{"WATCHDOG_SCALING_WEBHOOK_URL", "(none)", "webhook notified about significant upstream scaling"},
+	{"WATCHDOG_SCALING_THRESHOLD_PCT", fmt.Sprint(DefaultScalingThresholdPercent), "target count change in percent reported to the scaling webhook"},
+	{"WATCHDOG_DOCKER_SLOW_THRESHOLD", DefaultDockerSlowThreshold.String(), "95th percentile of container inspections considered slow"},
+	{"RATE_TIERS", "(none)", "json object mapping rate tiers to requests per minute"},
+	{"PROTECTED_SERVICES", "(none)", "services which are never removed automatically"},
+	{"WATCHDOG_DEBUG_CONFIG", "false", "print the optional variables at startup"},
+}
+
+// ValidateEnvironment checks that all required environment variables are set
+// and that all set variables have a valid format. Every problem found is
+// reported as a separate error
+func ValidateEnvironment() []error {
+	var errs []error
+	for _, name := range RequiredVariables {
+		if strings.TrimSpace(os.Getenv(name)) == "" {
+			errs = append(errs, fmt.Errorf("required environment variable %s is not set", name))
+		}
+	}
+
+	for _, name := range []string{"KONG_URL", "INTROSPECTION_URL"} {
+		value := strings.TrimSpace(os.Getenv(name))
+		if value == "" {
+			continue
+		}
+		if err := validateHTTPURL(value); err != nil {
+			errs = append(errs, fmt.Errorf("environment variable %s: %w", name, err))
+		}
+	}
+
+	if dockerHost, err := ParseDockerHost(os.Getenv("DOCKER_HOST")); err != nil {
+		errs = append(errs, err)
+	} else if err := CheckDockerTLS(dockerHost); err != nil {
+		errs = append(errs, err)
+	}
+
+	if err := ValidateNamespace(); err != nil {
+		errs = append(errs, err)
+	}
+	if err := ValidateNamePrefix(); err != nil {
+		errs = append(errs, err)
+	}
+
+	if authPluginConfig, err := AuthPluginConfiguration(); err != nil {
+		errs = append(errs, fmt.Errorf("environment variable %w", err))
+	} else if _, isSet := authPluginConfig["introspection_url"]; AuthPluginName == DefaultAuthPluginName && !isSet {
+		errs = append(errs, fmt.Errorf("required environment variable INTROSPECTION_URL is not set (needed by the auth plugin %s)", DefaultAuthPluginName))
+	}
+
+	for _, name := range []string{"KONG_WAIT_TIMEOUT", "DOCKER_WAIT_TIMEOUT"} {
+		if _, err := WaitTimeout(name); err != nil {
+			errs = append(errs, err)
+		}
+	}
+
+	if _, err := ScalingWebhookURL(); err != nil {
+		errs = append(errs, err)
+	}
+
+	if _, err := ScalingThresholdPercent(); err != nil {
+		errs = append(errs, err)
+	}
+
+	if _, err := DockerSlowThreshold(); err != nil {
+		errs = append(errs, err)
+	}
+
+	if _, err := RateTiers(); err != nil {
+		errs = append(errs, err)
+	}
+
+	_, routeDefaultErrors := RouteDefaults()
+	errs = append(errs, routeDefaultErrors...)
+
+	if debugConfig := strings.TrimSpace(os.Getenv("WATCHDOG_DEBUG_CONFIG")); debugConfig != "" &&
+		debugConfig != "true" && debugConfig != "false" {
+		errs = append(errs, fmt.Errorf("environment variable WATCHDOG_DEBUG_CONFIG needs to be 'true' or 'false'"))
+	}
+
+	return errs
+}
+
+// DebugConfigEnabled reports whether WATCHDOG_DEBUG_CONFIG is set to true
+func DebugConfigEnabled() bool {
+	return strings.TrimSpace(os.Getenv("WATCHDOG_DEBUG_CONFIG")) == "true"
+}
+
+// DescribeEnvironment writes the optional environment variables together
+// with their defaults and current values to w
+func DescribeEnvironment(w io.Writer) {
+	for _, variable := range OptionalVariables {
+		value, isSet := os.LookupEnv(variable.Name)
+		if !isSet {
+			value = "(not set)"
+		}
+		if (variable.Name == "AUTH_PLUGIN_CONFIG" || variable.Name == "KONG_TOKEN") && isSet {
+			value = "(set)"
+		}
+		fmt.Fprintf(w, "%s=%s (default: %s) - %s\n", variable.Name, value, variable.Default, variable.Description)
+	}
+}
+
+// validateHTTPURL checks that the value is an absolute http or https url
+func validateHTTPURL(value string) error {
+	parsedURL, err := url.Parse(value)
+	if err != nil {
+		return fmt.Errorf("'%s' is not a valid url: %w", value, err)
+	}
+	if parsedURL.Scheme != "http" && parsedURL.Scheme != "https" {
+		return fmt.Errorf("'%s' needs to use http or https", value)
+	}
+	if parsedURL.Host == "" {
+		return fmt.Errorf("'%s' does not contain a host", value)
+	}
+	return nil
+}
@@ -0,0 +1,181 @@
+package global
+
+import (
+	"bytes"
+	"strings"
+	"testing"
+)
+
+// setEnvironment clears every variable read by ValidateEnvironment and sets
+// the supplied ones for the duration of the test
+func setEnvironment(t *testing.T, authPluginName string, variables map[string]string) {
+	t.Helper()
+	for _, name := range RequiredVariables {
+		t.Setenv(name, "")
+	}
+	t.Setenv("INTROSPECTION_URL", "")
+	t.Setenv("DOCKER_TLS_VERIFY", "")
+	for _, variable := range OptionalVariables {
+		t.Setenv(variable.Name, "")
+	}
+	for name, value := range variables {
+		t.Setenv(name, value)
+	}
+
+	previousAuthPluginName := AuthPluginName
+	AuthPluginName = authPluginName
+	t.Cleanup(func() { AuthPluginName = previousAuthPluginName })
+}
+
+// requireErrors checks that exactly one error mentions each of the expected
+// substrings
+func requireErrors(t *testing.T, errs []error, expected ...string) {
+	t.Helper()
+	if len(errs) != len(expected) {
+		t.Fatalf("expected %d errors, got %d: %v", len(expected), len(errs), errs)
+	}
+	for _, substring := range expected {
+		found := false
+		for _, err := range errs {
+			if strings.Contains(err.Error(), substring) {
+				found = true
+				break
+			}
+		}
+		if !found {
+			t.Errorf("no error mentions '%s': %v", substring, errs)
+		}
+	}
+}
+
+func TestValidateEnvironmentValid(t *testing.T) {
+	setEnvironment(t, DefaultAuthPluginName, map[string]string{
+		"KONG_URL":                       "http://kong:8001",
+		"INTROSPECTION_URL":              "https://auth.example.com/introspect",
+		"DOCKER_HOST":                    "tcp://docker:2375",
+		"KONG_TOKEN":                     "secret",
+		"KONG_WAIT_TIMEOUT":              "30s",
+		"DOCKER_WAIT_TIMEOUT":            "1m",
+		"AUTH_PLUGIN_CONFIG":             `{"client_id":"watchdog"}`,
+		"DEFAULT_STRIP_PATH":             "false",
+		"DEFAULT_PRESERVE_HOST":          "true",
+		"DEFAULT_PROTOCOLS":              "http,https",
+		"DEFAULT_TAGS":                   "wisdom,managed",
+		"WATCHDOG_SCALING_WEBHOOK_URL":   "https://hooks.example.com/scaling",
+		"WATCHDOG_SCALING_THRESHOLD_PCT": "25",
+		"WATCHDOG_DOCKER_SLOW_THRESHOLD": "500ms",
+		"RATE_TIERS":                     `{"free":60,"premium":600}`,
+		"WATCHDOG_DEBUG_CONFIG":          "true",
+	})
+	if errs := ValidateEnvironment(); len(errs) != 0 {
+		t.Fatalf("unexpected errors: %v", errs)
+	}
+}
+
+func TestValidateEnvironmentMissingVariables(t *testing.T) {
+	setEnvironment(t, DefaultAuthPluginName, nil)
+	requireErrors(t, ValidateEnvironment(), "KONG_URL", "INTROSPECTION_URL")
+}
+
+func TestValidateEnvironmentIntrospectionURL(t *testing.T) {
+	tests := []struct {
+		name           string
+		authPluginName string
+		variables      map[string]string
+		wantError      bool
+	}{
+		{"default plugin without url", DefaultAuthPluginName, nil, true},
+		{"default plugin with url", DefaultAuthPluginName, map[string]string{"INTROSPECTION_URL": "http://auth/introspect"}, false},
+		{"default plugin with url in config", DefaultAuthPluginName, map[string]string{"AUTH_PLUGIN_CONFIG": `{"introspection_url":"http://auth/introspect"}`}, false},
+		{"other plugin without url", "key-auth", nil, false},
+	}
+	for _, test := range tests {
+		t.Run(test.name, func(t *testing.T) {
+			variables := map[string]string{"KONG_URL": "http://kong:8001"}
+			for name, value := range test.variables {
+				variables[name] = value
+			}
+			setEnvironment(t, test.authPluginName, variables)
+			errs := ValidateEnvironment()
+			if test.wantError {
+				requireErrors(t, errs, "INTROSPECTION_URL")
+			} else if len(errs) != 0 {
+				t.Fatalf("unexpected errors: %v", errs)
+			}
+		})
+	}
+}
+
+func TestValidateEnvironmentMalformedURLs(t *testing.T) {
+	setEnvironment(t, DefaultAuthPluginName, map[string]string{
+		"KONG_URL":                     "kong:8001",
+		"INTROSPECTION_URL":            "ftp://auth/introspect",
+		"WATCHDOG_SCALING_WEBHOOK_URL": "https://",
+	})
+	requireErrors(t, ValidateEnvironment(), "KONG_URL", "INTROSPECTION_URL", "WATCHDOG_SCALING_WEBHOOK_URL")
+}
+
+func TestValidateEnvironmentMalformedValues(t *testing.T) {
+	setEnvironment(t, "key-auth", map[string]string{
+		"KONG_URL":                       "http://kong:8001",
+		"DOCKER_HOST":                    "ssh://docker",
+		"AUTH_PLUGIN_CONFIG":             `{"client_id":`,
+		"KONG_WAIT_TIMEOUT":              "soon",
+		"DEFAULT_STRIP_PATH":             "maybe",
+		"DEFAULT_PROTOCOLS":              "ftp",
+		"WATCHDOG_SCALING_THRESHOLD_PCT": "-5",
+		"WATCHDOG_DOCKER_SLOW_THRESHOLD": "0s",
+		"RATE_TIERS":                     `{"free":0}`,
+		"WATCHDOG_DEBUG_CONFIG":          "yes",
+	})
+	requireErrors(t, ValidateEnvironment(), "DOCKER_HOST", "AUTH_PLUGIN_CONFIG", "KONG_WAIT_TIMEOUT",
+		"DEFAULT_STRIP_PATH", "DEFAULT_PROTOCOLS", "WATCHDOG_SCALING_THRESHOLD_PCT",
+		"WATCHDOG_DOCKER_SLOW_THRESHOLD", "RATE_TIERS", "WATCHDOG_DEBUG_CONFIG")
+}
+
+func TestValidateEnvironmentDockerTLS(t *testing.T) {
+	tests := []struct {
+		name      string
+		variables map[string]string
+	}{
+		{"tls port", map[string]string{"DOCKER_HOST": "tcp://docker:2376"}},
+		{"tls verify", map[string]string{"DOCKER_HOST": "tcp://docker:2375", "DOCKER_TLS_VERIFY": "1"}},
+	}
+	for _, test := range tests {
+		t.Run(test.name, func(t *testing.T) {
+			variables := map[string]string{"KONG_URL": "http://kong:8001"}
+			for name, value := range test.variables {
+				variables[name] = value
+			}
+			setEnvironment(t, "key-auth", variables)
+			requireErrors(t, ValidateEnvironment(), "TLS")
+		})
+	}
+}
+
+func TestValidateEnvironmentScope(t *testing.T) {
+	setEnvironment(t, "key-auth", map[string]string{"KONG_URL": "http://kong:8001"})
+	setScope(t, "prod/eu", "a b")
+	requireErrors(t, ValidateEnvironment(), "WATCHDOG_NAMESPACE", "NAME_PREFIX")
+}
+
+func TestDescribeEnvironment(t *testing.T) {
+	setEnvironment(t, DefaultAuthPluginName, map[string]string{
+		"WATCHDOG_NAMESPACE": "staging",
+		"AUTH_PLUGIN_CONFIG": `{"client_secret":"hidden"}`,
+		"KONG_TOKEN":         "hidden",
+	})
+	var output bytes.Buffer
+	DescribeEnvironment(&output)
+	description := output.String()
+
+	if strings.Count(description, "\n") != len(OptionalVariables) {
+		t.Errorf("expected one line per optional variable, got:\n%s", description)
+	}
+	if !strings.Contains(description, "WATCHDOG_NAMESPACE=staging (default: (none))") {
+		t.Errorf("the namespace is not described:\n%s", description)
+	}
+	if strings.Contains(description, "hidden") {
+		t.Errorf("a secret has been printed:\n%s", description)
+	}
+}
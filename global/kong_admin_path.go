@@ -0,0 +1,68 @@
+package global
+
+import (
+	"net/http"
+	"os"
+	"strings"
+)
+
+// KongAdminPathPrefix is read from WATCHDOG_KONG_ADMIN_PATH_PREFIX and is the
+// path under which the Kong admin api is reachable if it is proxied, e.g.
+// "/kong-admin"
+var KongAdminPathPrefix = normalizePathPrefix(os.Getenv("WATCHDOG_KONG_ADMIN_PATH_PREFIX"))
+
+// normalizePathPrefix returns the prefix with a leading and without a
+// trailing slash. An empty or root prefix is returned as empty string
+func normalizePathPrefix(prefix string) string {
+	prefix = strings.Trim(strings.TrimSpace(prefix), "/")
+	if prefix == "" {
+		return ""
+	}
+	return "/" + prefix
+}
+
+// KongAdminURL appends the admin path prefix to the url of the Kong admin
+// api. A url already ending with the prefix is returned without the
+// trailing slash only
+func KongAdminURL(kongURL string) string {
+	kongURL = strings.TrimSuffix(kongURL, "/")
+	if strings.HasSuffix(kongURL, KongAdminPathPrefix) {
+		return kongURL
+	}
+	return kongURL + KongAdminPathPrefix
+}
+
+// PathPrefixTransport prepends a path prefix to the path of every request.
+// It is used for clients which build their request urls from the host of
+// the admin api only
+type PathPrefixTransport struct {
+	// Prefix is prepended to the request paths
+	Prefix string
+	// Base executes the rewritten requests. http.DefaultTransport is used
+	// if it is nil
+	Base http.RoundTripper
+}
+
+// RoundTrip rewrites the request path and passes the request on to the base
+// transport. Requests already using the prefix are not rewritten
+func (t *PathPrefixTransport) RoundTrip(request *http.Request) (*http.Response, error) {
+	base := t.Base
+	if base == nil {
+		base = http.DefaultTransport
+	}
+	prefix := normalizePathPrefix(t.Prefix)
+	if prefix == "" || request.URL.Path == prefix || strings.HasPrefix(request.URL.Path, prefix+"/") {
+		return base.RoundTrip(request)
+	}
+
+	rewrittenRequest := request.Clone(request.Context())
+	requestPath := request.URL.Path
+	if !strings.HasPrefix(requestPath, "/") {
+		requestPath = "/" + requestPath
+	}
+	rewrittenRequest.URL.Path = prefix + requestPath
+	if request.URL.RawPath != "" {
+		rewrittenRequest.URL.RawPath = prefix + request.URL.RawPath
+	}
+	return base.RoundTrip(rewrittenRequest)
+}
@@ -0,0 +1,102 @@
+package global
+
+import (
+	"context"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+	"time"
+)
+
+// setKongAdminPathPrefix sets the admin path prefix for the duration of the
+// test
+func setKongAdminPathPrefix(t *testing.T, prefix string) {
+	t.Helper()
+	previousPrefix := KongAdminPathPrefix
+	KongAdminPathPrefix = normalizePathPrefix(prefix)
+	t.Cleanup(func() { KongAdminPathPrefix = previousPrefix })
+}
+
+func TestKongAdminURL(t *testing.T) {
+	tests := []struct {
+		prefix   string
+		kongURL  string
+		expected string
+	}{
+		{"", "http://kong:8001", "http://kong:8001"},
+		{"", "http://kong:8001/", "http://kong:8001"},
+		{"kong-admin/", "http://proxy", "http://proxy/kong-admin"},
+		{"/kong-admin", "http://proxy/", "http://proxy/kong-admin"},
+		{"/kong-admin", "http://proxy/kong-admin/", "http://proxy/kong-admin"},
+	}
+	for _, test := range tests {
+		setKongAdminPathPrefix(t, test.prefix)
+		if adminURL := KongAdminURL(test.kongURL); adminURL != test.expected {
+			t.Errorf("KongAdminURL(%q) with prefix %q: expected %q, got %q", test.kongURL, test.prefix, test.expected, adminURL)
+		}
+	}
+}
+
+func TestWaitForKongUsesPathPrefix(t *testing.T) {
+	setKongAdminPathPrefix(t, "/kong-admin")
+
+	var requestedPaths []string
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		requestedPaths = append(requestedPaths, r.URL.Path)
+		if r.URL.Path != "/kong-admin/status" {
+			w.WriteHeader(http.StatusNotFound)
+		}
+	}))
+	defer server.Close()
+
+	if err := WaitForKong(context.Background(), server.URL, time.Second, nil); err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	if len(requestedPaths) != 1 || requestedPaths[0] != "/kong-admin/status" {
+		t.Errorf("expected a single request to /kong-admin/status, got %v", requestedPaths)
+	}
+}
+
+func TestCheckKongTokenUsesPathPrefix(t *testing.T) {
+	setKongAdminPathPrefix(t, "/kong-admin")
+
+	var requestedPaths []string
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		requestedPaths = append(requestedPaths, r.URL.Path)
+		if r.URL.Path != "/kong-admin/" {
+			w.WriteHeader(http.StatusNotFound)
+		}
+	}))
+	defer server.Close()
+
+	if err := CheckKongToken(context.Background(), server.URL, "secret"); err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	if len(requestedPaths) != 1 || requestedPaths[0] != "/kong-admin/" {
+		t.Errorf("expected a single request to /kong-admin/, got %v", requestedPaths)
+	}
+}
+
+func TestPathPrefixTransport(t *testing.T) {
+	var requestedPath string
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		requestedPath = r.URL.Path
+	}))
+	defer server.Close()
+
+	client := &http.Client{Transport: &PathPrefixTransport{Prefix: "kong-admin"}}
+	for path, expected := range map[string]string{
+		"/services":            "/kong-admin/services",
+		"/kong-admin/services": "/kong-admin/services",
+		"/kong-administration": "/kong-admin/kong-administration",
+	} {
+		response, err := client.Get(server.URL + path)
+		if err != nil {
+			t.Fatalf("unexpected error: %v", err)
+		}
+		response.Body.Close()
+		if requestedPath != expected {
+			t.Errorf("request to %s: expected path %s, got %s", path, expected, requestedPath)
+		}
+	}
+}
@@ -0,0 +1,52 @@
+package global
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"net/http"
+	"os"
+	"strings"
+)
+
+// KongAdminTokenHeader is the header carrying the token for the Kong admin
+// API
+const KongAdminTokenHeader = "Kong-Admin-Token"
+
+// KongToken is read from KONG_TOKEN and sent with every request to the Kong
+// admin API. Without a token no header is sent
+var KongToken = strings.TrimSpace(os.Getenv("KONG_TOKEN"))
+
+// ErrKongTokenRejected is returned by CheckKongToken if the Kong admin API
+// does not accept the token
+var ErrKongTokenRejected = errors.New("the Kong admin API rejected KONG_TOKEN")
+
+// CheckKongToken sends a read-only request with the token to the Kong admin
+// API. A 401 or 403 response is reported as ErrKongTokenRejected, so the
+// watchdog can exit at startup instead of failing every Kong call later on.
+// The admin path prefix is applied to the url, see KongAdminURL
+func CheckKongToken(ctx context.Context, kongURL, token string) error {
+	request, err := http.NewRequestWithContext(ctx, http.MethodGet, KongAdminURL(kongURL)+"/", nil)
+	if err != nil {
+		return fmt.Errorf("invalid Kong url '%s': %w", kongURL, err)
+	}
+	request.Header.Set("User-Agent", UserAgent())
+	if token != "" {
+		request.Header.Set(KongAdminTokenHeader, token)
+	}
+	response, err := http.DefaultClient.Do(request)
+	if err != nil {
+		return fmt.Errorf("unable to reach the Kong admin API: %w", err)
+	}
+	defer response.Body.Close()
+	switch {
+	case response.StatusCode == http.StatusUnauthorized || response.StatusCode == http.StatusForbidden:
+		if token == "" {
+			return fmt.Errorf("%w (%s): the admin API requires a token, set KONG_TOKEN", ErrKongTokenRejected, response.Status)
+		}
+		return fmt.Errorf("%w (%s): check that the token is current", ErrKongTokenRejected, response.Status)
+	case response.StatusCode >= http.StatusBadRequest:
+		return fmt.Errorf("GET %s returned %s", request.URL, response.Status)
+	}
+	return nil
+}
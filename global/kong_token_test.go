@@ -0,0 +1,58 @@
+package global
+
+import (
+	"context"
+	"errors"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+)
+
+func TestCheckKongToken(t *testing.T) {
+	tests := []struct {
+		name      string
+		token     string
+		status    int
+		wantError bool
+		rejected  bool
+	}{
+		{"accepted token", "secret", http.StatusOK, false, false},
+		{"no token required", "", http.StatusOK, false, false},
+		{"rotated token", "rotated", http.StatusUnauthorized, true, true},
+		{"missing token", "", http.StatusUnauthorized, true, true},
+		{"forbidden", "secret", http.StatusForbidden, true, true},
+		{"server error", "secret", http.StatusInternalServerError, true, false},
+	}
+	for _, test := range tests {
+		t.Run(test.name, func(t *testing.T) {
+			server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+				if r.Method != http.MethodGet || r.URL.Path != "/" {
+					t.Errorf("unexpected request %s %s", r.Method, r.URL.Path)
+				}
+				if token := r.Header.Get(KongAdminTokenHeader); token != test.token {
+					t.Errorf("expected the token '%s', got '%s'", test.token, token)
+				}
+				w.WriteHeader(test.status)
+			}))
+			defer server.Close()
+
+			err := CheckKongToken(context.Background(), server.URL, test.token)
+			if (err != nil) != test.wantError {
+				t.Fatalf("expected error %v, got %v", test.wantError, err)
+			}
+			if rejected := errors.Is(err, ErrKongTokenRejected); rejected != test.rejected {
+				t.Errorf("expected the token to be rejected %v, got %v", test.rejected, err)
+			}
+		})
+	}
+}
+
+func TestCheckKongTokenUnreachable(t *testing.T) {
+	server := httptest.NewServer(http.NotFoundHandler())
+	server.Close()
+
+	err := CheckKongToken(context.Background(), server.URL, "secret")
+	if err == nil || errors.Is(err, ErrKongTokenRejected) {
+		t.Errorf("expected a connection error, got %v", err)
+	}
+}
@@ -0,0 +1,74 @@
+package global
+
+import (
+	"fmt"
+	"os"
+	"regexp"
+	"strings"
+)
+
+// NamespaceSeparator separates the namespace from the name of a Kong object.
+// Kong rejects a slash in service names and upstream names need to be valid
+// hostnames, so a dot is used
+const NamespaceSeparator = "."
+
+// Namespace is read from WATCHDOG_NAMESPACE and scopes the names of all Kong
+// objects created by the watchdog. An empty namespace disables the scoping
+var Namespace = strings.TrimSpace(os.Getenv("WATCHDOG_NAMESPACE"))
+
+// namespacePattern matches a hostname label, as the namespace becomes part
+// of the upstream names
+var namespacePattern = regexp.MustCompile(`^[a-zA-Z0-9]([a-zA-Z0-9-]{0,61}[a-zA-Z0-9])?$`)
+
+// ValidateNamespace checks that the namespace may be used in the names of
+// Kong objects
+func ValidateNamespace() error {
+	if Namespace != "" && !namespacePattern.MatchString(Namespace) {
+		return fmt.Errorf("WATCHDOG_NAMESPACE '%s' needs to be a hostname label of up to 63 letters, digits and hyphens", Namespace)
+	}
+	return nil
+}
+
+// NamePrefix is read from NAME_PREFIX and is prepended to the names of the
+// Kong services, routes and upstreams created by the watchdog, e.g.
+// "staging-". Route paths are not affected by the prefix
+var NamePrefix = strings.TrimSpace(os.Getenv("NAME_PREFIX"))
+
+// namePrefixPattern matches the start of a hostname label, as the prefix is
+// prepended to the upstream names
+var namePrefixPattern = regexp.MustCompile(`^[a-zA-Z0-9][a-zA-Z0-9-]{0,61}$`)
+
+// ValidateNamePrefix checks that the name prefix may be used in the names of
+// Kong objects
+func ValidateNamePrefix() error {
+	if NamePrefix != "" && !namePrefixPattern.MatchString(NamePrefix) {
+		return fmt.Errorf("NAME_PREFIX '%s' needs to start with a letter or digit followed by letters, digits and hyphens", NamePrefix)
+	}
+	return nil
+}
+
+// scope returns the part which is prepended to all Kong object names
+func scope() string {
+	if Namespace == "" {
+		return NamePrefix
+	}
+	return Namespace + NamespaceSeparator + NamePrefix
+}
+
+// ScopedName prefixes the name with the namespace and name prefix of the
+// watchdog. The name is returned unchanged if neither is configured
+func ScopedName(name string) string {
+	return scope() + name
+}
+
+// InNamespace reports whether a Kong object name belongs to the namespace
+// and name prefix of the watchdog. Without either every name belongs to it
+func InNamespace(name string) bool {
+	return strings.HasPrefix(name, scope())
+}
+
+// UnscopedName removes the namespace and name prefix from a Kong object
+// name. Names outside the namespace are returned unchanged
+func UnscopedName(name string) string {
+	return strings.TrimPrefix(name, scope())
+}
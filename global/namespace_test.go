@@ -0,0 +1,93 @@
+package global
+
+import "testing"
+
+// setScope sets the namespace and name prefix for the duration of the test
+func setScope(t *testing.T, namespace, namePrefix string) {
+	previousNamespace, previousNamePrefix := Namespace, NamePrefix
+	Namespace, NamePrefix = namespace, namePrefix
+	t.Cleanup(func() { Namespace, NamePrefix = previousNamespace, previousNamePrefix })
+}
+
+func TestScopedName(t *testing.T) {
+	tests := []struct {
+		namespace   string
+		namePrefix  string
+		name        string
+		scoped      string
+		inNamespace []string
+		outside     []string
+	}{
+		{"", "", "users", "users", []string{"users", "staging.users"}, nil},
+		{"staging", "", "users", "staging.users", []string{"staging.users"}, []string{"users", "prod.users", "stagingusers"}},
+		{"", "wisdom-", "users", "wisdom-users", []string{"wisdom-users"}, []string{"users", "staging.wisdom-users"}},
+		{"staging", "wisdom-", "users", "staging.wisdom-users", []string{"staging.wisdom-users"}, []string{"staging.users", "wisdom-users"}},
+	}
+	for _, test := range tests {
+		t.Run(test.namespace+"/"+test.namePrefix, func(t *testing.T) {
+			setScope(t, test.namespace, test.namePrefix)
+			scoped := ScopedName(test.name)
+			if scoped != test.scoped {
+				t.Errorf("expected '%s', got '%s'", test.scoped, scoped)
+			}
+			if unscoped := UnscopedName(scoped); unscoped != test.name {
+				t.Errorf("expected '%s' without the scope, got '%s'", test.name, unscoped)
+			}
+			for _, name := range test.inNamespace {
+				if !InNamespace(name) {
+					t.Errorf("expected '%s' to be in the namespace", name)
+				}
+			}
+			for _, name := range test.outside {
+				if InNamespace(name) {
+					t.Errorf("expected '%s' to be outside the namespace", name)
+				}
+				if unscoped := UnscopedName(name); unscoped != name {
+					t.Errorf("expected '%s' outside the namespace to stay unchanged, got '%s'", name, unscoped)
+				}
+			}
+		})
+	}
+}
+
+func TestValidateNamespace(t *testing.T) {
+	tests := []struct {
+		namespace string
+		wantError bool
+	}{
+		{"", false},
+		{"staging", false},
+		{"eu-west-1", false},
+		{"prod/eu", true},
+		{"a b", true},
+		{"prod.eu", true},
+		{"-staging", true},
+	}
+	for _, test := range tests {
+		setScope(t, test.namespace, "")
+		if err := ValidateNamespace(); (err != nil) != test.wantError {
+			t.Errorf("%q: expected error %v, got %v", test.namespace, test.wantError, err)
+		}
+	}
+}
+
+func TestValidateNamePrefix(t *testing.T) {
+	tests := []struct {
+		namePrefix string
+		wantError  bool
+	}{
+		{"", false},
+		{"staging-", false},
+		{"wisdom", false},
+		{"prod/eu", true},
+		{"a b", true},
+		{"prod.", true},
+		{"-staging", true},
+	}
+	for _, test := range tests {
+		setScope(t, "", test.namePrefix)
+		if err := ValidateNamePrefix(); (err != nil) != test.wantError {
+			t.Errorf("%q: expected error %v, got %v", test.namePrefix, test.wantError, err)
+		}
+	}
+}
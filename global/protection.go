@@ -0,0 +1,45 @@
+package global
+
+import (
+	"os"
+
+	"gateway-service-watcher/structs"
+)
+
+// ProtectedTag marks Kong objects of protected services. Storing the
+// protection in Kong keeps it intact across restarts of the watchdog
+const ProtectedTag = "wisdom-protected"
+
+// ProtectedServices contains the service names from the comma-separated
+// PROTECTED_SERVICES environment variable
+var ProtectedServices = parseProtectedServices(os.Getenv("PROTECTED_SERVICES"))
+
+func parseProtectedServices(value string) map[string]bool {
+	services := make(map[string]bool)
+	for _, service := range structs.SplitList(value) {
+		services[service] = true
+	}
+	return services
+}
+
+// IsProtected reports whether a Kong object may not be removed
+// automatically. This is the case if its service is listed in
+// PROTECTED_SERVICES or the object carries the ProtectedTag.
+//
+// The service name may be passed unscoped, as set by the service name label,
+// or scoped, as used for the Kong object (see ScopedName). PROTECTED_SERVICES
+// may list either form, so the name is also looked up in the other form
+func IsProtected(serviceName string, tags []string) bool {
+	if ProtectedServices[serviceName] || ProtectedServices[ScopedName(serviceName)] {
+		return true
+	}
+	if InNamespace(serviceName) && ProtectedServices[UnscopedName(serviceName)] {
+		return true
+	}
+	for _, tag := range tags {
+		if tag == ProtectedTag {
+			return true
+		}
+	}
+	return false
+}
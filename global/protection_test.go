@@ -0,0 +1,43 @@
+package global
+
+import "testing"
+
+func TestParseProtectedServices(t *testing.T) {
+	services := parseProtectedServices(" users, ,orders,")
+	if len(services) != 2 || !services["users"] || !services["orders"] {
+		t.Errorf("expected the services users and orders, got %v", services)
+	}
+	if services := parseProtectedServices(""); len(services) != 0 {
+		t.Errorf("expected no services, got %v", services)
+	}
+}
+
+func TestIsProtected(t *testing.T) {
+	setScope(t, "staging", "wisdom-")
+	previousServices := ProtectedServices
+	ProtectedServices = parseProtectedServices(" users, ,staging.wisdom-auth ")
+	t.Cleanup(func() { ProtectedServices = previousServices })
+
+	tests := []struct {
+		name        string
+		serviceName string
+		tags        []string
+		expected    bool
+	}{
+		{"unscoped name listed", "users", nil, true},
+		{"scoped name of listed service", "staging.wisdom-users", nil, true},
+		{"scoped name listed", "staging.wisdom-auth", nil, true},
+		{"unscoped name of scoped entry", "auth", nil, true},
+		{"other namespace", "production.wisdom-users", nil, false},
+		{"not listed", "staging.wisdom-orders", nil, false},
+		{"unscoped name not listed", "orders", nil, false},
+		{"protected tag", "staging.wisdom-orders", []string{"managed", ProtectedTag}, true},
+	}
+	for _, test := range tests {
+		t.Run(test.name, func(t *testing.T) {
+			if protected := IsProtected(test.serviceName, test.tags); protected != test.expected {
+				t.Errorf("expected %v, got %v", test.expected, protected)
+			}
+		})
+	}
+}
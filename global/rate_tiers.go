@@ -0,0 +1,43 @@
+package global
+
+import (
+	"encoding/json"
+	"fmt"
+	"os"
+	"strings"
+)
+
+// RateTiers reads the rate tiers from RATE_TIERS. The variable contains a
+// json object mapping the name of each tier to the number of requests per
+// minute its consumers may send, e.g. {"basic":60,"premium":600}. Without
+// RATE_TIERS no tiers are returned and the feature is disabled
+func RateTiers() (map[string]int, error) {
+	rawTiers := strings.TrimSpace(os.Getenv("RATE_TIERS"))
+	if rawTiers == "" {
+		return nil, nil
+	}
+	var tiers map[string]int
+	if err := json.Unmarshal([]byte(rawTiers), &tiers); err != nil {
+		return nil, fmt.Errorf("environment variable RATE_TIERS does not map tier names to requests per minute: %w", err)
+	}
+	for name, requestsPerMinute := range tiers {
+		if strings.TrimSpace(name) == "" {
+			return nil, fmt.Errorf("environment variable RATE_TIERS contains a tier without name")
+		}
+		if requestsPerMinute <= 0 {
+			return nil, fmt.Errorf("environment variable RATE_TIERS: rate tier '%s' needs to allow at least one request per minute", name)
+		}
+	}
+	return tiers, nil
+}
+
+// UnknownRateTiers returns the requested tiers which are not defined
+func UnknownRateTiers(tiers map[string]int, requestedTiers []string) []string {
+	var unknownTiers []string
+	for _, tier := range requestedTiers {
+		if _, defined := tiers[tier]; !defined {
+			unknownTiers = append(unknownTiers, tier)
+		}
+	}
+	return unknownTiers
+}
@@ -0,0 +1,37 @@
+package global
+
+import (
+	"reflect"
+	"testing"
+)
+
+func TestRateTiers(t *testing.T) {
+	tests := []struct {
+		value     string
+		expected  map[string]int
+		wantError bool
+	}{
+		{"", nil, false},
+		{`{"free":60,"premium":600}`, map[string]int{"free": 60, "premium": 600}, false},
+		{`{"free":0}`, nil, true},
+		{`{" ":60}`, nil, true},
+		{`["free"]`, nil, true},
+	}
+	for _, test := range tests {
+		t.Setenv("RATE_TIERS", test.value)
+		tiers, err := RateTiers()
+		if (err != nil) != test.wantError || !reflect.DeepEqual(tiers, test.expected) {
+			t.Errorf("%q: expected %v (error: %v), got %v (%v)", test.value, test.expected, test.wantError, tiers, err)
+		}
+	}
+}
+
+func TestUnknownRateTiers(t *testing.T) {
+	tiers := map[string]int{"free": 60, "premium": 600}
+	if unknownTiers := UnknownRateTiers(tiers, []string{"free", "gold", "premium", "silver"}); !reflect.DeepEqual(unknownTiers, []string{"gold", "silver"}) {
+		t.Errorf("expected the unknown tiers [gold silver], got %v", unknownTiers)
+	}
+	if unknownTiers := UnknownRateTiers(tiers, []string{"free"}); unknownTiers != nil {
+		t.Errorf("unexpected unknown tiers %v", unknownTiers)
+	}
+}
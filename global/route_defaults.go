@@ -0,0 +1,52 @@
+package global
+
+import (
+	"fmt"
+	"os"
+	"strconv"
+	"strings"
+
+	"gateway-service-watcher/structs"
+)
+
+// RouteDefaults reads the route options applied to services which do not
+// set them with a label from DEFAULT_STRIP_PATH, DEFAULT_PRESERVE_HOST,
+// DEFAULT_PROTOCOLS and DEFAULT_TAGS. Options without a default stay nil so
+// the Kong default applies
+func RouteDefaults() (structs.RouteOptions, []error) {
+	var defaults structs.RouteOptions
+	var errs []error
+
+	if value := strings.TrimSpace(os.Getenv("DEFAULT_STRIP_PATH")); value != "" {
+		stripPath, err := strconv.ParseBool(value)
+		if err != nil {
+			errs = append(errs, fmt.Errorf("environment variable DEFAULT_STRIP_PATH needs to be a boolean"))
+		} else {
+			defaults.StripPath = &stripPath
+		}
+	}
+
+	if value := strings.TrimSpace(os.Getenv("DEFAULT_PRESERVE_HOST")); value != "" {
+		preserveHost, err := strconv.ParseBool(value)
+		if err != nil {
+			errs = append(errs, fmt.Errorf("environment variable DEFAULT_PRESERVE_HOST needs to be a boolean"))
+		} else {
+			defaults.PreserveHost = &preserveHost
+		}
+	}
+
+	if value := strings.TrimSpace(os.Getenv("DEFAULT_PROTOCOLS")); value != "" {
+		protocols, err := structs.ParseProtocols(value)
+		if err != nil {
+			errs = append(errs, fmt.Errorf("environment variable DEFAULT_PROTOCOLS: %w", err))
+		} else {
+			defaults.Protocols = protocols
+		}
+	}
+
+	if value := strings.TrimSpace(os.Getenv("DEFAULT_TAGS")); value != "" {
+		defaults.Tags = structs.SplitList(value)
+	}
+
+	return defaults, errs
+}
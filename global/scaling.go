@@ -0,0 +1,41 @@
+package global
+
+import (
+	"fmt"
+	"os"
+	"strconv"
+	"strings"
+)
+
+// DefaultScalingThresholdPercent is the change of the target count of an
+// upstream which triggers a scaling event if no threshold is configured
+const DefaultScalingThresholdPercent = 50
+
+// ScalingWebhookURL reads the url of the webhook notified about scaling
+// events from WATCHDOG_SCALING_WEBHOOK_URL. An empty url disables the
+// notifications
+func ScalingWebhookURL() (string, error) {
+	webhookURL := strings.TrimSpace(os.Getenv("WATCHDOG_SCALING_WEBHOOK_URL"))
+	if webhookURL == "" {
+		return "", nil
+	}
+	if err := validateHTTPURL(webhookURL); err != nil {
+		return "", fmt.Errorf("environment variable WATCHDOG_SCALING_WEBHOOK_URL: %w", err)
+	}
+	return webhookURL, nil
+}
+
+// ScalingThresholdPercent reads the change of the target count in percent
+// which triggers a scaling event from WATCHDOG_SCALING_THRESHOLD_PCT. If the
+// variable is not set DefaultScalingThresholdPercent is returned
+func ScalingThresholdPercent() (float64, error) {
+	value := strings.TrimSpace(os.Getenv("WATCHDOG_SCALING_THRESHOLD_PCT"))
+	if value == "" {
+		return DefaultScalingThresholdPercent, nil
+	}
+	threshold, err := strconv.ParseFloat(value, 64)
+	if err != nil || threshold <= 0 {
+		return 0, fmt.Errorf("environment variable WATCHDOG_SCALING_THRESHOLD_PCT needs to be a positive number")
+	}
+	return threshold, nil
+}
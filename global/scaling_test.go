@@ -0,0 +1,42 @@
+package global
+
+import "testing"
+
+func TestScalingThresholdPercent(t *testing.T) {
+	tests := []struct {
+		value     string
+		expected  float64
+		wantError bool
+	}{
+		{"", DefaultScalingThresholdPercent, false},
+		{"12.5", 12.5, false},
+		{"0", 0, true},
+		{"half", 0, true},
+	}
+	for _, test := range tests {
+		t.Setenv("WATCHDOG_SCALING_THRESHOLD_PCT", test.value)
+		threshold, err := ScalingThresholdPercent()
+		if (err != nil) != test.wantError || threshold != test.expected {
+			t.Errorf("%q: expected %v (error: %v), got %v (%v)", test.value, test.expected, test.wantError, threshold, err)
+		}
+	}
+}
+
+func TestScalingWebhookURL(t *testing.T) {
+	tests := []struct {
+		value     string
+		expected  string
+		wantError bool
+	}{
+		{"", "", false},
+		{" https://hooks.example.com/scaling ", "https://hooks.example.com/scaling", false},
+		{"hooks.example.com/scaling", "", true},
+	}
+	for _, test := range tests {
+		t.Setenv("WATCHDOG_SCALING_WEBHOOK_URL", test.value)
+		webhookURL, err := ScalingWebhookURL()
+		if (err != nil) != test.wantError || webhookURL != test.expected {
+			t.Errorf("%q: expected %q (error: %v), got %q (%v)", test.value, test.expected, test.wantError, webhookURL, err)
+		}
+	}
+}
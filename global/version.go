@@ -0,0 +1,59 @@
+package global
+
+import (
+	"fmt"
+	"runtime/debug"
+)
+
+// Build information of the watchdog. The values are set while building the
+// binary, e.g.:
+//
+//	go build -ldflags "-X gateway-service-watcher/global.Version=v1.0.0 \
+//	  -X gateway-service-watcher/global.Commit=$(git rev-parse HEAD) \
+//	  -X gateway-service-watcher/global.BuildDate=$(date -u +%FT%TZ)"
+//
+// Values which are not set are read from the build information embedded by
+// the go toolchain
+var (
+	Version   = ""
+	Commit    = ""
+	BuildDate = ""
+)
+
+const unknownBuildValue = "unknown"
+
+func init() {
+	buildInfo, available := debug.ReadBuildInfo()
+	if available {
+		if Version == "" && buildInfo.Main.Version != "" && buildInfo.Main.Version != "(devel)" {
+			Version = buildInfo.Main.Version
+		}
+		for _, setting := range buildInfo.Settings {
+			switch setting.Key {
+			case "vcs.revision":
+				if Commit == "" {
+					Commit = setting.Value
+				}
+			case "vcs.time":
+				if BuildDate == "" {
+					BuildDate = setting.Value
+				}
+			}
+		}
+	}
+	if Version == "" {
+		Version = "dev"
+	}
+	if Commit == "" {
+		Commit = unknownBuildValue
+	}
+	if BuildDate == "" {
+		BuildDate = unknownBuildValue
+	}
+}
+
+// UserAgent returns the value for the User-Agent header of requests sent by
+// the watchdog
+func UserAgent() string {
+	return fmt.Sprintf("wisdom-oss-watchdog/%s (%s)", Version, Commit)
+}
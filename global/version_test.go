@@ -0,0 +1,21 @@
+package global
+
+import "testing"
+
+func TestBuildInformation(t *testing.T) {
+	for name, value := range map[string]string{"Version": Version, "Commit": Commit, "BuildDate": BuildDate} {
+		if value == "" {
+			t.Errorf("%s has not been set", name)
+		}
+	}
+}
+
+func TestUserAgent(t *testing.T) {
+	version, commit := Version, Commit
+	t.Cleanup(func() { Version, Commit = version, commit })
+
+	Version, Commit = "v1.2.3", "0a1b2c3"
+	if userAgent := UserAgent(); userAgent != "wisdom-oss-watchdog/v1.2.3 (0a1b2c3)" {
+		t.Errorf("unexpected user agent '%s'", userAgent)
+	}
+}
@@ -0,0 +1,144 @@
+package global
+
+import (
+	"context"
+	"fmt"
+	"net"
+	"net/http"
+	"os"
+	"strings"
+	"time"
+)
+
+// DefaultWaitTimeout is used if no wait timeout is configured
+const DefaultWaitTimeout = 60 * time.Second
+
+const (
+	initialWaitBackoff = 500 * time.Millisecond
+	maximumWaitBackoff = 10 * time.Second
+)
+
+// RetryFunc is called after every failed readiness check with the number of
+// the failed attempt, the error and the delay until the next attempt. It may
+// be used to log the progress of waiting
+type RetryFunc func(attempt int, err error, delay time.Duration)
+
+// WaitTimeout reads a wait timeout from the environment variable. If the
+// variable is not set DefaultWaitTimeout is returned
+func WaitTimeout(name string) (time.Duration, error) {
+	value := strings.TrimSpace(os.Getenv(name))
+	if value == "" {
+		return DefaultWaitTimeout, nil
+	}
+	timeout, err := time.ParseDuration(value)
+	if err != nil {
+		return 0, fmt.Errorf("environment variable %s does not contain a duration: %w", name, err)
+	}
+	if timeout <= 0 {
+		return 0, fmt.Errorf("environment variable %s needs to be positive", name)
+	}
+	return timeout, nil
+}
+
+// waitFor calls check until it succeeds, the timeout passes or the context
+// is cancelled. The delay between two checks doubles up to a maximum
+func waitFor(ctx context.Context, timeout time.Duration, check func(ctx context.Context) error, onRetry RetryFunc) error {
+	ctx, cancel := context.WithTimeout(ctx, timeout)
+	defer cancel()
+
+	delay := initialWaitBackoff
+	for attempt := 1; ; attempt++ {
+		err := check(ctx)
+		if err == nil {
+			return nil
+		}
+		if onRetry != nil {
+			onRetry(attempt, err, delay)
+		}
+		select {
+		case <-ctx.Done():
+			return fmt.Errorf("not ready after %s: %w", timeout, err)
+		case <-time.After(delay):
+		}
+		delay = nextWaitBackoff(delay)
+	}
+}
+
+// nextWaitBackoff doubles the delay between two checks up to
+// maximumWaitBackoff
+func nextWaitBackoff(delay time.Duration) time.Duration {
+	if delay *= 2; delay > maximumWaitBackoff {
+		return maximumWaitBackoff
+	}
+	return delay
+}
+
+// WaitForKong polls the status endpoint of the Kong admin API until it
+// answers successfully or the timeout passes. The admin path prefix is
+// applied to the url, see KongAdminURL
+func WaitForKong(ctx context.Context, kongURL string, timeout time.Duration, onRetry RetryFunc) error {
+	statusURL := KongAdminURL(kongURL) + "/status"
+	err := waitFor(ctx, timeout, func(ctx context.Context) error {
+		request, err := http.NewRequestWithContext(ctx, http.MethodGet, statusURL, nil)
+		if err != nil {
+			return err
+		}
+		request.Header.Set("User-Agent", UserAgent())
+		response, err := http.DefaultClient.Do(request)
+		if err != nil {
+			return err
+		}
+		response.Body.Close()
+		if response.StatusCode != http.StatusOK {
+			return fmt.Errorf("GET %s returned %s", statusURL, response.Status)
+		}
+		return nil
+	}, onRetry)
+	if err != nil {
+		return fmt.Errorf("kong admin api at %s: %w", kongURL, err)
+	}
+	return nil
+}
+
+// WaitForDocker pings the docker daemon until it answers or the timeout
+// passes. Hosts expecting TLS are rejected without waiting
+func WaitForDocker(ctx context.Context, host DockerHost, timeout time.Duration, onRetry RetryFunc) error {
+	if err := CheckDockerTLS(host); err != nil {
+		return err
+	}
+	dialer := &net.Dialer{Timeout: 5 * time.Second}
+	client := &http.Client{
+		Transport: &http.Transport{
+			DialContext: func(ctx context.Context, _, _ string) (net.Conn, error) {
+				return dialer.DialContext(ctx, host.Network, host.Address)
+			},
+		},
+	}
+	defer client.CloseIdleConnections()
+
+	// the host part of the url is ignored since the dialer always connects
+	// to the docker host
+	pingURL := "http://docker/_ping"
+	if host.Network == "tcp" {
+		pingURL = "http://" + host.Address + "/_ping"
+	}
+	err := waitFor(ctx, timeout, func(ctx context.Context) error {
+		request, err := http.NewRequestWithContext(ctx, http.MethodGet, pingURL, nil)
+		if err != nil {
+			return err
+		}
+		response, err := client.Do(request)
+		if err != nil {
+			return err
+		}
+		response.Body.Close()
+		if response.StatusCode != http.StatusOK {
+			return fmt.Errorf("ping returned %s", response.Status)
+		}
+		return nil
+	}, onRetry)
+	if err != nil {
+		return fmt.Errorf("docker daemon at %s://%s: %w", host.Network, host.Address, err)
+	}
+	return nil
+}
@@ -0,0 +1,164 @@
+package global
+
+import (
+	"context"
+	"errors"
+	"net"
+	"net/http"
+	"net/http/httptest"
+	"path/filepath"
+	"strings"
+	"sync/atomic"
+	"testing"
+	"time"
+)
+
+func TestNextWaitBackoff(t *testing.T) {
+	delay := initialWaitBackoff
+	var delays []time.Duration
+	for i := 0; i < 8; i++ {
+		delays = append(delays, delay)
+		delay = nextWaitBackoff(delay)
+	}
+	expected := []time.Duration{
+		500 * time.Millisecond, time.Second, 2 * time.Second, 4 * time.Second, 8 * time.Second,
+		maximumWaitBackoff, maximumWaitBackoff, maximumWaitBackoff,
+	}
+	for i := range expected {
+		if delays[i] != expected[i] {
+			t.Errorf("attempt %d: expected a delay of %s, got %s", i+1, expected[i], delays[i])
+		}
+	}
+}
+
+func TestWaitForTimeout(t *testing.T) {
+	checkErr := errors.New("connection refused")
+	var attempts []int
+	err := waitFor(context.Background(), 20*time.Millisecond, func(ctx context.Context) error {
+		return checkErr
+	}, func(attempt int, err error, delay time.Duration) {
+		attempts = append(attempts, attempt)
+		if delay != initialWaitBackoff {
+			t.Errorf("unexpected delay %s before the second attempt", delay)
+		}
+	})
+	if err == nil || !errors.Is(err, checkErr) || !strings.Contains(err.Error(), "not ready after 20ms") {
+		t.Errorf("expected a timeout error wrapping the last check error, got %v", err)
+	}
+	if len(attempts) != 1 || attempts[0] != 1 {
+		t.Errorf("expected a single failed attempt, got %v", attempts)
+	}
+}
+
+func TestWaitForCancel(t *testing.T) {
+	ctx, cancel := context.WithCancel(context.Background())
+	start := time.Now()
+	err := waitFor(ctx, time.Minute, func(ctx context.Context) error {
+		return errors.New("not ready")
+	}, func(int, error, time.Duration) { cancel() })
+	if err == nil {
+		t.Fatalf("expected an error after cancelling the context")
+	}
+	if elapsed := time.Since(start); elapsed >= initialWaitBackoff {
+		t.Errorf("waiting did not stop when the context was cancelled, it took %s", elapsed)
+	}
+}
+
+func TestWaitForKong(t *testing.T) {
+	var requests int32
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		if r.URL.Path != "/status" {
+			t.Errorf("unexpected request for %s", r.URL.Path)
+		}
+		if atomic.AddInt32(&requests, 1) == 1 {
+			w.WriteHeader(http.StatusServiceUnavailable)
+		}
+	}))
+	defer server.Close()
+
+	if err := WaitForKong(context.Background(), server.URL+"/", 5*time.Second, nil); err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	if requests != 2 {
+		t.Errorf("expected a retry after the failed status request, got %d requests", requests)
+	}
+}
+
+func TestWaitTimeout(t *testing.T) {
+	tests := []struct {
+		value     string
+		expected  time.Duration
+		wantError bool
+	}{
+		{"", DefaultWaitTimeout, false},
+		{"90s", 90 * time.Second, false},
+		{"0s", 0, true},
+		{"soon", 0, true},
+	}
+	for _, test := range tests {
+		t.Setenv("KONG_WAIT_TIMEOUT", test.value)
+		timeout, err := WaitTimeout("KONG_WAIT_TIMEOUT")
+		if (err != nil) != test.wantError || timeout != test.expected {
+			t.Errorf("%q: expected %s (error: %v), got %s (%v)", test.value, test.expected, test.wantError, timeout, err)
+		}
+	}
+}
+
+// newDockerServer starts a test server answering the docker ping on a unix
+// socket in a temporary directory
+func newDockerServer(t *testing.T, handler http.HandlerFunc) DockerHost {
+	socketPath := filepath.Join(t.TempDir(), "docker.sock")
+	listener, err := net.Listen("unix", socketPath)
+	if err != nil {
+		t.Fatalf("unable to listen on a unix socket: %v", err)
+	}
+	server := httptest.NewUnstartedServer(handler)
+	server.Listener = listener
+	server.Start()
+	t.Cleanup(server.Close)
+	return DockerHost{Network: "unix", Address: socketPath}
+}
+
+func TestWaitForDocker(t *testing.T) {
+	t.Setenv("DOCKER_TLS_VERIFY", "")
+	var requests int32
+	host := newDockerServer(t, func(w http.ResponseWriter, r *http.Request) {
+		if r.URL.Path != "/_ping" {
+			t.Errorf("unexpected request for %s", r.URL.Path)
+		}
+		if atomic.AddInt32(&requests, 1) == 1 {
+			w.WriteHeader(http.StatusInternalServerError)
+			return
+		}
+		w.Write([]byte("OK"))
+	})
+
+	if err := WaitForDocker(context.Background(), host, 5*time.Second, nil); err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	if requests != 2 {
+		t.Errorf("expected a retry after the failed ping, got %d requests", requests)
+	}
+}
+
+func TestWaitForDockerTimeout(t *testing.T) {
+	t.Setenv("DOCKER_TLS_VERIFY", "")
+	host := DockerHost{Network: "unix", Address: filepath.Join(t.TempDir(), "missing.sock")}
+	err := WaitForDocker(context.Background(), host, 20*time.Millisecond, nil)
+	if err == nil || !strings.Contains(err.Error(), "not ready after 20ms") {
+		t.Errorf("expected a timeout error, got %v", err)
+	}
+}
+
+func TestWaitForDockerTLS(t *testing.T) {
+	t.Setenv("DOCKER_TLS_VERIFY", "")
+	var attempts int
+	err := WaitForDocker(context.Background(), DockerHost{Network: "tcp", Address: "127.0.0.1:2376"}, time.Minute,
+		func(int, error, time.Duration) { attempts++ })
+	if err == nil || !strings.Contains(err.Error(), "TLS") {
+		t.Errorf("expected the TLS port to be rejected, got %v", err)
+	}
+	if attempts != 0 {
+		t.Errorf("expected no connection attempts, got %d", attempts)
+	}
+}
@@ -1,3 +1,14 @@
 module gateway-service-watcher
 
 go 1.20
+
+require (
+	github.com/google/uuid v1.6.0
+	github.com/rs/zerolog v1.33.0
+)
+
+require (
+	github.com/mattn/go-colorable v0.1.13 // indirect
+	github.com/mattn/go-isatty v0.0.19 // indirect
+	golang.org/x/sys v0.12.0 // indirect
+)
@@ -0,0 +1,25 @@
+package labels
+
+import (
+	"fmt"
+	"strings"
+)
+
+// CheckNetworks verifies that a container is attached to at least one of the
+// allowed docker networks. Containers failing the check may not be
+// registered, regardless of their labels. Without allowed networks every
+// container passes
+func CheckNetworks(containerNetworks []string, allowedNetworks []string) error {
+	if len(allowedNetworks) == 0 {
+		return nil
+	}
+	for _, network := range containerNetworks {
+		for _, allowedNetwork := range allowedNetworks {
+			if network == allowedNetwork {
+				return nil
+			}
+		}
+	}
+	return fmt.Errorf("the container is attached to [%s] but only [%s] are allowed",
+		strings.Join(containerNetworks, ", "), strings.Join(allowedNetworks, ", "))
+}
@@ -0,0 +1,57 @@
+package labels
+
+import (
+	"strings"
+	"testing"
+
+	"gateway-service-watcher/structs"
+)
+
+func TestCheckNetworks(t *testing.T) {
+	tests := []struct {
+		name              string
+		containerNetworks []string
+		allowedNetworks   []string
+		wantError         bool
+	}{
+		{"no allowed networks", []string{"backend"}, nil, false},
+		{"no allowed networks and no container networks", nil, nil, false},
+		{"attached to allowed network", []string{"frontend", "backend"}, []string{"backend"}, false},
+		{"not attached to allowed network", []string{"frontend"}, []string{"backend", "gateway"}, true},
+		{"container without networks", nil, []string{"backend"}, true},
+	}
+	for _, test := range tests {
+		t.Run(test.name, func(t *testing.T) {
+			err := CheckNetworks(test.containerNetworks, test.allowedNetworks)
+			if (err != nil) != test.wantError {
+				t.Errorf("expected error: %v, got %v", test.wantError, err)
+			}
+		})
+	}
+}
+
+func TestParseAllowedNetworks(t *testing.T) {
+	containerLabels := serviceLabels(nil)
+
+	_, problems := Parse(containerLabels,
+		WithContainer("users-1", []string{"frontend"}),
+		WithAllowedNetworks([]string{"backend"}))
+	if len(problems) != 1 || problems[0].Kind != structs.NetworkNotAllowed {
+		t.Fatalf("expected a single %s problem, got %v", structs.NetworkNotAllowed, problems)
+	}
+	if !strings.Contains(problems[0].Error(), "users-1") {
+		t.Errorf("problem does not name the container: %v", problems[0])
+	}
+
+	_, problems = Parse(containerLabels,
+		WithContainer("users-1", []string{"frontend", "backend"}),
+		WithAllowedNetworks([]string{"backend"}))
+	if len(problems) != 0 {
+		t.Errorf("unexpected problems: %v", problems)
+	}
+
+	_, problems = Parse(containerLabels, WithContainer("users-1", []string{"frontend"}))
+	if len(problems) != 0 {
+		t.Errorf("unexpected problems without allowed networks: %v", problems)
+	}
+}
@@ -0,0 +1,55 @@
+package labels
+
+import (
+	"gateway-service-watcher/global"
+	"gateway-service-watcher/structs"
+)
+
+// Option changes how Parse validates a container
+type Option func(*parseOptions)
+
+type parseOptions struct {
+	routeDefaults     structs.RouteOptions
+	containerName     string
+	containerNetworks []string
+	allowedNetworks   []string
+}
+
+// WithRouteDefaults sets the route options used for route option labels
+// which are not set on the container. Options neither set by a label nor by
+// the defaults are left to Kong
+func WithRouteDefaults(defaults structs.RouteOptions) Option {
+	return func(options *parseOptions) {
+		options.routeDefaults = defaults
+	}
+}
+
+// WithContainer supplies the name and the docker networks of the container
+// whose labels are parsed. The name is used in problems which are not caused
+// by a single label
+func WithContainer(name string, networks []string) Option {
+	return func(options *parseOptions) {
+		options.containerName = name
+		options.containerNetworks = networks
+	}
+}
+
+// WithAllowedNetworks reports containers which are not attached to one of
+// the networks, see CheckNetworks. The networks of the container are
+// supplied with WithContainer
+func WithAllowedNetworks(networks []string) Option {
+	return func(options *parseOptions) {
+		options.allowedNetworks = networks
+	}
+}
+
+// EnvironmentOptions returns the options configured by the environment
+// variables of the watchdog. Invalid route defaults are reported by
+// global.ValidateEnvironment and are left to Kong here
+func EnvironmentOptions() []Option {
+	routeDefaults, _ := global.RouteDefaults()
+	return []Option{
+		WithRouteDefaults(routeDefaults),
+		WithAllowedNetworks(global.AllowedNetworks),
+	}
+}
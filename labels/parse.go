@@ -0,0 +1,103 @@
+// Package labels extracts the gateway configuration of a service container
+// from its labels
+package labels
+
+import (
+	"errors"
+	"fmt"
+	"strconv"
+	"strings"
+
+	"gateway-service-watcher/structs"
+	"gateway-service-watcher/utils"
+)
+
+// Problem describes a single label which is missing or could not be used.
+// The Kind of a problem lets callers decide whether to skip the container,
+// warn about it or register it with the remaining configuration
+type Problem = structs.ValidationError
+
+// requiredLabels are reported as missing if they are not set. Their values
+// may still be provided by the information endpoint of the service
+var requiredLabels = []string{
+	structs.ServiceNameLabel,
+	structs.ServicePathLabel,
+	structs.ServiceUpstreamNameLabel,
+}
+
+// Parse extracts and validates the gateway configuration from the labels of
+// a container. The returned configuration contains every value which could
+// be parsed, even if problems have been reported for other labels. Containers
+// which are not marked as service, or opted out with the service label set
+// to false, are reported with a MissingLabel or NotAService problem and must
+// not be registered. The same applies to containers reported with a
+// NetworkNotAllowed problem if allowed networks are supplied as option
+func Parse(containerLabels map[string]string, options ...Option) (structs.GatewayConfiguration, []Problem) {
+	var parseOptions parseOptions
+	for _, option := range options {
+		option(&parseOptions)
+	}
+
+	var problems []Problem
+
+	isService, isSet := containerLabels[structs.ServiceLabel]
+	if !isSet {
+		problems = append(problems, Problem{
+			Kind:   structs.MissingLabel,
+			Label:  structs.ServiceLabel,
+			Reason: "the container is not marked as service",
+		})
+	} else if markedAsService, err := strconv.ParseBool(strings.TrimSpace(isService)); err != nil {
+		problems = append(problems, Problem{
+			Kind:   structs.InvalidBool,
+			Label:  structs.ServiceLabel,
+			Value:  isService,
+			Reason: "expected a boolean",
+		})
+	} else if !markedAsService {
+		problems = append(problems, Problem{
+			Kind:   structs.NotAService,
+			Label:  structs.ServiceLabel,
+			Value:  isService,
+			Reason: "the container opted out of the registration",
+		})
+	}
+
+	if err := CheckNetworks(parseOptions.containerNetworks, parseOptions.allowedNetworks); err != nil {
+		problems = append(problems, Problem{
+			Kind:   structs.NetworkNotAllowed,
+			Value:  strings.Join(parseOptions.containerNetworks, ","),
+			Reason: fmt.Sprintf("container '%s' may not be registered: %v", parseOptions.containerName, err),
+		})
+	}
+
+	for _, label := range requiredLabels {
+		if _, isSet := containerLabels[label]; !isSet {
+			problems = append(problems, Problem{
+				Kind:   structs.MissingLabel,
+				Label:  label,
+				Reason: "the label is not set on the container",
+			})
+		}
+	}
+
+	var corsProblem Problem
+	if err := utils.ValidateCORSConfig(containerLabels); errors.As(err, &corsProblem) {
+		problems = append(problems, corsProblem)
+	}
+
+	config, validationErrors := structs.NewGatewayConfigurationBuilder(containerLabels).
+		WithRouteDefaults(parseOptions.routeDefaults).
+		Build()
+	return config, append(problems, validationErrors...)
+}
+
+// HasKind reports whether any of the problems is of the supplied kind
+func HasKind(problems []Problem, kind structs.ValidationErrorKind) bool {
+	for _, problem := range problems {
+		if problem.Kind == kind {
+			return true
+		}
+	}
+	return false
+}
@@ -0,0 +1,218 @@
+package labels
+
+import (
+	"go/ast"
+	"go/parser"
+	"go/token"
+	"io/fs"
+	"reflect"
+	"strconv"
+	"strings"
+	"testing"
+
+	"gateway-service-watcher/structs"
+	"gateway-service-watcher/utils"
+)
+
+// serviceLabels returns the labels of a valid service container together
+// with the supplied extra labels
+func serviceLabels(extra map[string]string) map[string]string {
+	containerLabels := map[string]string{
+		structs.ServiceLabel:             "true",
+		structs.ServiceNameLabel:         "users",
+		structs.ServicePathLabel:         "/users",
+		structs.ServiceUpstreamNameLabel: "users",
+	}
+	for label, value := range extra {
+		containerLabels[label] = value
+	}
+	return containerLabels
+}
+
+// labelCase is a value of a label together with the kind of the problem it
+// is expected to cause
+type labelCase struct {
+	value string
+	kind  structs.ValidationErrorKind
+}
+
+// labelTests contains the valid and invalid values of every label read by
+// Parse. A label without invalid values accepts every value
+var labelTests = []struct {
+	label   string
+	extra   map[string]string
+	valid   []string
+	invalid []labelCase
+	// required labels are reported as missing if they are absent
+	required bool
+}{
+	{label: structs.ServiceLabel, valid: []string{"true", " 1 "}, invalid: []labelCase{{"yes", structs.InvalidBool}, {"false", structs.NotAService}}, required: true},
+	{label: structs.ServiceNameLabel, valid: []string{"orders"}, invalid: []labelCase{{" ", structs.InvalidValue}}, required: true},
+	{label: structs.ServicePathLabel, valid: []string{"/api/orders"}, invalid: []labelCase{{"orders", structs.InvalidPath}, {"/orders?all", structs.InvalidPath}}, required: true},
+	{label: structs.ServicePathRegexLabel, valid: []string{`/api/v\d+/orders`, `~/api/orders/(?!internal)`}, invalid: []labelCase{{"orders", structs.InvalidPath}, {"/api/(orders", structs.InvalidPath}}},
+	{label: structs.ServiceUpstreamNameLabel, valid: []string{"orders"}, invalid: []labelCase{{"", structs.InvalidValue}}, required: true},
+	{label: structs.ServiceHealthcheckLabel, valid: []string{"false"}, invalid: []labelCase{{"maybe", structs.InvalidBool}}},
+	{label: structs.ServiceRetriesLabel, valid: []string{"0", "32767"}, invalid: []labelCase{{"often", structs.InvalidInt}, {"-1", structs.OutOfRange}, {"32768", structs.OutOfRange}}},
+	{label: structs.ServiceWeightLabel, valid: []string{"0", "65535"}, invalid: []labelCase{{"heavy", structs.InvalidInt}, {"65536", structs.OutOfRange}}},
+	{label: structs.ServiceTimeoutLabel, valid: []string{"30s", "1ms"}, invalid: []labelCase{{"30", structs.InvalidDuration}, {"-1s", structs.OutOfRange}, {"999us", structs.OutOfRange}}},
+	{label: structs.ServiceMaxTargetsLabel, valid: []string{"1"}, invalid: []labelCase{{"many", structs.InvalidInt}, {"0", structs.OutOfRange}}},
+	{label: structs.ServiceIDLabel, valid: []string{"3f6c9a4e-7a8b-4c3d-9e2f-1a2b3c4d5e6f"}, invalid: []labelCase{{"orders-1", structs.InvalidValue}}},
+	{label: structs.ServiceEnabledLabel, valid: []string{"true", "false"}, invalid: []labelCase{{"off", structs.InvalidBool}}},
+	{label: structs.ServiceRouteNameLabel, valid: []string{"orders-route"}, invalid: []labelCase{{"orders_route", structs.InvalidValue}, {"-orders", structs.InvalidValue}}},
+	{label: structs.ServiceHTTPSOnlyLabel, valid: []string{"true"}, invalid: []labelCase{{"yes", structs.InvalidBool}}},
+	{label: utils.CORSOriginsLabel, valid: []string{"*", "https://a.example.com"}},
+	{
+		label:   utils.CORSCredentialsLabel,
+		extra:   map[string]string{utils.CORSOriginsLabel: "https://a.example.com"},
+		valid:   []string{"true", "false"},
+		invalid: []labelCase{{"sometimes", structs.InvalidBool}},
+	},
+	{label: structs.ServiceStripPathLabel, valid: []string{"false"}, invalid: []labelCase{{"no", structs.InvalidBool}}},
+	{label: structs.ServicePreserveHostLabel, valid: []string{"true"}, invalid: []labelCase{{"yes", structs.InvalidBool}}},
+	{label: structs.ServiceProtocolsLabel, valid: []string{"http, https", "grpcs"}, invalid: []labelCase{{"ftp", structs.InvalidValue}, {",", structs.InvalidValue}}},
+	{label: structs.ServiceTagsLabel, valid: []string{"orders,managed", ""}},
+	{label: structs.ServiceDiscoveryLabel, valid: []string{"docker", "consul"}, invalid: []labelCase{{"dns", structs.InvalidValue}}},
+	{
+		label:   structs.ServiceConsulServiceNameLabel,
+		extra:   map[string]string{structs.ServiceDiscoveryLabel: structs.ConsulServiceDiscovery},
+		valid:   []string{"orders-api"},
+		invalid: []labelCase{{" ", structs.InvalidValue}},
+	},
+	{label: structs.ServiceDescriptionLabel, valid: []string{"Manages orders", ""}},
+	{label: structs.ServiceDocsURLLabel, valid: []string{"https://docs.example.com/orders"}, invalid: []labelCase{{"ftp://docs.example.com", structs.InvalidValue}, {"/docs", structs.InvalidValue}}},
+	{label: structs.ServiceOpenAPIPathLabel, valid: []string{"/openapi.json"}, invalid: []labelCase{{"openapi.json", structs.InvalidPath}}},
+	{label: structs.ServiceUpstreamPathLabel, valid: []string{"/v1"}, invalid: []labelCase{{"v1", structs.InvalidPath}}},
+	{label: structs.ServiceResponseCodeMappingLabel, valid: []string{"404:410;500:503"}, invalid: []labelCase{{"404", structs.InvalidValue}, {"404:600", structs.InvalidValue}}},
+	{label: structs.ServiceRateTiersLabel, valid: []string{"basic, premium"}, invalid: []labelCase{{" , ", structs.InvalidValue}}},
+	{label: structs.ServiceMirrorOfLabel, valid: []string{"orders"}, invalid: []labelCase{{"users", structs.InvalidValue}, {"", structs.InvalidValue}}},
+	{label: structs.ServiceMaxRequestSizeLabel, valid: []string{"1", "2048"}, invalid: []labelCase{{"large", structs.InvalidInt}, {"2049", structs.OutOfRange}}},
+	{label: structs.ServiceProtectedLabel, valid: []string{"true"}, invalid: []labelCase{{"always", structs.InvalidBool}}},
+}
+
+func TestParseLabels(t *testing.T) {
+	for _, test := range labelTests {
+		t.Run(test.label, func(t *testing.T) {
+			for _, value := range test.valid {
+				containerLabels := serviceLabels(test.extra)
+				containerLabels[test.label] = value
+				if _, problems := Parse(containerLabels); len(problems) != 0 {
+					t.Errorf("valid value %q: unexpected problems: %v", value, problems)
+				}
+			}
+
+			for _, invalid := range test.invalid {
+				containerLabels := serviceLabels(test.extra)
+				containerLabels[test.label] = invalid.value
+				_, problems := Parse(containerLabels)
+				if len(problems) != 1 || problems[0].Label != test.label || problems[0].Kind != invalid.kind {
+					t.Errorf("invalid value %q: expected a single %s problem, got %v", invalid.value, invalid.kind, problems)
+				}
+			}
+
+			containerLabels := serviceLabels(test.extra)
+			delete(containerLabels, test.label)
+			_, problems := Parse(containerLabels)
+			if !test.required {
+				if len(problems) != 0 {
+					t.Errorf("absent label: unexpected problems: %v", problems)
+				}
+				return
+			}
+			if len(problems) != 1 || problems[0].Label != test.label || problems[0].Kind != structs.MissingLabel {
+				t.Errorf("absent label: expected a single %s problem, got %v", structs.MissingLabel, problems)
+			}
+		})
+	}
+}
+
+// TestParseLabelsComplete checks that labelTests covers every label constant
+// of the structs package
+func TestParseLabelsComplete(t *testing.T) {
+	packages, err := parser.ParseDir(token.NewFileSet(), "../structs", func(info fs.FileInfo) bool {
+		return !strings.HasSuffix(info.Name(), "_test.go")
+	}, 0)
+	if err != nil {
+		t.Fatalf("unable to parse the structs package: %v", err)
+	}
+
+	tested := make(map[string]bool)
+	for _, test := range labelTests {
+		tested[test.label] = true
+	}
+	for _, file := range packages["structs"].Files {
+		for _, declaration := range file.Decls {
+			genericDeclaration, isGeneric := declaration.(*ast.GenDecl)
+			if !isGeneric || genericDeclaration.Tok != token.CONST {
+				continue
+			}
+			for _, spec := range genericDeclaration.Specs {
+				valueSpec := spec.(*ast.ValueSpec)
+				for index, name := range valueSpec.Names {
+					if !strings.HasPrefix(name.Name, "Service") || !strings.HasSuffix(name.Name, "Label") {
+						continue
+					}
+					literal, isLiteral := valueSpec.Values[index].(*ast.BasicLit)
+					if !isLiteral {
+						t.Errorf("unable to read the value of %s", name.Name)
+						continue
+					}
+					if label, _ := strconv.Unquote(literal.Value); !tested[label] {
+						t.Errorf("structs.%s (%s) is not covered by labelTests", name.Name, label)
+					}
+				}
+			}
+		}
+	}
+}
+
+func TestHasKind(t *testing.T) {
+	_, problems := Parse(map[string]string{structs.ServiceRetriesLabel: "often"})
+	if !HasKind(problems, structs.MissingLabel) || !HasKind(problems, structs.InvalidInt) {
+		t.Errorf("expected missing label and invalid int problems, got %v", problems)
+	}
+	if HasKind(problems, structs.InvalidPath) {
+		t.Errorf("unexpected invalid path problem in %v", problems)
+	}
+}
+
+func TestParseRouteDefaults(t *testing.T) {
+	stripPath := false
+	defaults := structs.RouteOptions{StripPath: &stripPath, Protocols: []string{"https"}}
+
+	config, problems := Parse(serviceLabels(map[string]string{
+		structs.ServiceProtocolsLabel: "http,https",
+	}), WithRouteDefaults(defaults))
+	if len(problems) != 0 {
+		t.Fatalf("unexpected problems: %v", problems)
+	}
+	if config.RouteOptions.StripPath == nil || *config.RouteOptions.StripPath {
+		t.Errorf("expected strip path from the defaults, got %v", config.RouteOptions.StripPath)
+	}
+	if !reflect.DeepEqual(config.RouteOptions.Protocols, []string{"http", "https"}) {
+		t.Errorf("expected protocols from the label, got %v", config.RouteOptions.Protocols)
+	}
+	if config.RouteOptions.PreserveHost != nil {
+		t.Errorf("expected preserve host to be left to Kong, got %v", *config.RouteOptions.PreserveHost)
+	}
+}
+
+func TestEnvironmentOptionsRouteDefaults(t *testing.T) {
+	t.Setenv("DEFAULT_STRIP_PATH", "")
+	t.Setenv("DEFAULT_PRESERVE_HOST", "true")
+	t.Setenv("DEFAULT_PROTOCOLS", "https")
+	t.Setenv("DEFAULT_TAGS", "")
+
+	config, problems := Parse(serviceLabels(nil), EnvironmentOptions()...)
+	if len(problems) != 0 {
+		t.Fatalf("unexpected problems: %v", problems)
+	}
+	if config.RouteOptions.PreserveHost == nil || !*config.RouteOptions.PreserveHost {
+		t.Errorf("expected preserve host from DEFAULT_PRESERVE_HOST, got %v", config.RouteOptions.PreserveHost)
+	}
+	if !reflect.DeepEqual(config.RouteOptions.Protocols, []string{"https"}) {
+		t.Errorf("expected protocols from DEFAULT_PROTOCOLS, got %v", config.RouteOptions.Protocols)
+	}
+	if config.RouteOptions.StripPath != nil {
+		t.Errorf("expected strip path to be left to Kong, got %v", *config.RouteOptions.StripPath)
+	}
+}
@@ -0,0 +1,160 @@
+package structs
+
+import (
+	"fmt"
+	"time"
+)
+
+// Labels which are read from a service container to configure its entry in
+// the API gateway
+const (
+	ServiceLabel             = "wisdom-oss.isService"
+	ServiceEnabledLabel      = "wisdom-oss.service.enabled"
+	ServiceNameLabel         = "wisdom-oss.service.name"
+	ServiceIDLabel           = "wisdom-oss.service.id"
+	ServicePathLabel         = "wisdom-oss.service.path"
+	ServicePathRegexLabel    = "wisdom-oss.service.path-regex"
+	ServiceUpstreamPathLabel = "wisdom-oss.service.upstream-path"
+	ServiceRouteNameLabel    = "wisdom-oss.service.route-name"
+	ServiceUpstreamNameLabel = "wisdom-oss.service.upstream-name"
+	ServiceHealthcheckLabel  = "wisdom-oss.service.healthcheck"
+	ServiceRetriesLabel      = "wisdom-oss.service.retries"
+	ServiceWeightLabel       = "wisdom-oss.service.weight"
+	ServiceTimeoutLabel      = "wisdom-oss.service.timeout"
+	ServiceMaxTargetsLabel   = "wisdom-oss.service.max-targets"
+	ServiceHTTPSOnlyLabel    = "wisdom-oss.service.https-only"
+
+	ServiceDiscoveryLabel           = "wisdom-oss.service.service-discovery"
+	ServiceConsulServiceNameLabel   = "wisdom-oss.service.consul-service-name"
+	ServiceDescriptionLabel         = "wisdom-oss.service.description"
+	ServiceDocsURLLabel             = "wisdom-oss.service.docs-url"
+	ServiceOpenAPIPathLabel         = "wisdom-oss.service.openapi-path"
+	ServiceResponseCodeMappingLabel = "wisdom-oss.service.response-code-mapping"
+	ServiceRateTiersLabel           = "wisdom-oss.service.rate-tiers"
+	ServiceMirrorOfLabel            = "wisdom-oss.service.mirror-of"
+	ServiceMaxRequestSizeLabel      = "wisdom-oss.service.max-request-size"
+	ServiceProtectedLabel           = "wisdom-oss.service.protected"
+)
+
+// Mechanisms used to resolve the address of a service's targets
+const (
+	// DockerServiceDiscovery registers the container hostname as target
+	DockerServiceDiscovery = "docker"
+	// ConsulServiceDiscovery registers the consul dns name of the service as
+	// target and lets Kong resolve it
+	ConsulServiceDiscovery = "consul"
+)
+
+// MaxRequestSizeLimit is the largest value accepted for the maximal request
+// size of a service in megabytes
+const MaxRequestSizeLimit = 2048
+
+// DefaultTargetWeight is the weight a target receives in its upstream if the
+// container does not set a weight
+const DefaultTargetWeight = 100
+
+// MaxRetries is the largest number of retries Kong accepts for a service
+const MaxRetries = 32767
+
+// MinTimeout is the shortest timeout Kong accepts for a service, as Kong
+// configures its timeouts in whole milliseconds
+const MinTimeout = time.Millisecond
+
+// GatewayConfiguration contains the configuration of a service container
+// as it has been parsed from the container's labels. Optional fields are
+// pointers which stay nil if the value was neither set on the container nor
+// has a default, letting Kong apply its own default instead
+type GatewayConfiguration struct {
+	// Enabled indicates whether the service should be registered in the
+	// gateway. Disabled services are deregistered without stopping their
+	// containers
+	Enabled bool
+	// ServiceName is the name of the service in the API gateway
+	ServiceName string
+	// ServiceID is used as ID of the Kong service when it is created, which
+	// keeps the ID stable if the service is recreated
+	ServiceID *string
+	// Path is the path under which the service is reachable in the gateway.
+	// If PathRegex is set, Path is only used as path of the Kong service
+	Path string
+	// UpstreamPath is the path of the Kong service, i.e. the path requests
+	// are forwarded to on the upstream. If it is empty Path is used
+	UpstreamPath string
+	// PathRegex is a regular expression used for the paths of the Kong
+	// route. It always starts with the "~" Kong requires for regex paths.
+	// Stripping a regex path removes the whole matched part of the request
+	// path, so strip_path should usually be disabled for these routes
+	PathRegex string
+	// RouteName is the name of the Kong route of the service. If it is
+	// empty the route name is derived from the service name
+	RouteName string
+	// UpstreamName is the name of the upstream the container is added to
+	UpstreamName string
+	// Healthcheck indicates whether the gateway checks the health of the
+	// container's target
+	Healthcheck *bool
+	// Retries is the number of retries Kong executes if proxying fails
+	Retries *int
+	// Weight is the weight of the container's target in the upstream
+	Weight *int
+	// Timeout is used for the connect, read and write timeouts of the
+	// Kong service
+	Timeout *time.Duration
+	// MaxTargets limits the number of targets in the upstream of the service
+	MaxTargets *int
+	// HTTPSOnly restricts the route of the service to https. Requests using
+	// http are redirected with a 308 status code
+	HTTPSOnly bool
+	// RouteOptions are the options of the Kong route of the service
+	RouteOptions RouteOptions
+	// ServiceDiscovery is either DockerServiceDiscovery or
+	// ConsulServiceDiscovery
+	ServiceDiscovery string
+	// ConsulServiceName is the name of the service in consul. It is only used
+	// with ConsulServiceDiscovery and defaults to the upstream name
+	ConsulServiceName string
+	// Description is a short description of the service which is stored
+	// on the Kong service for the developer portal
+	Description string
+	// DocsURL points to the documentation of the service
+	DocsURL string
+	// OpenAPIPath is the path under which the container serves its openapi
+	// specification
+	OpenAPIPath string
+	// ResponseCodeMapping maps status codes returned by the upstream to the
+	// status codes returned by the gateway
+	ResponseCodeMapping map[int]int
+	// RateTiers restricts the service to consumers of the listed rate tiers
+	RateTiers []string
+	// MirrorOf is the name of the service whose traffic is mirrored to the
+	// targets of this service
+	MirrorOf string
+	// MaxRequestSize is the largest request payload in megabytes which is
+	// forwarded to the service
+	MaxRequestSize *int
+	// Protected services are never removed from the gateway automatically
+	Protected bool
+}
+
+// ServicePath returns the path of the Kong service which is prepended to the
+// requests forwarded to the upstream
+func (c GatewayConfiguration) ServicePath() string {
+	if c.UpstreamPath != "" {
+		return c.UpstreamPath
+	}
+	return c.Path
+}
+
+// EffectiveRouteName returns the name of the Kong route of the service
+func (c GatewayConfiguration) EffectiveRouteName() string {
+	if c.RouteName != "" {
+		return c.RouteName
+	}
+	return c.ServiceName + "-route"
+}
+
+// ConsulTarget returns the address of the target using the consul dns name
+// of the service
+func (c GatewayConfiguration) ConsulTarget(port int) string {
+	return fmt.Sprintf("%s.service.consul:%d", c.ConsulServiceName, port)
+}
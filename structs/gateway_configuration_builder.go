@@ -0,0 +1,579 @@
+package structs
+
+import (
+	"errors"
+	"fmt"
+	"net/url"
+	"regexp"
+	"regexp/syntax"
+	"strconv"
+	"strings"
+	"time"
+
+	"github.com/google/uuid"
+)
+
+// ValidationErrorKind describes why a label has been rejected
+type ValidationErrorKind int
+
+const (
+	// InvalidValue is used for values which are not accepted for a label
+	InvalidValue ValidationErrorKind = iota
+	// MissingLabel is used for labels which are required but not set
+	MissingLabel
+	// InvalidBool is used for values which are not a boolean
+	InvalidBool
+	// InvalidInt is used for values which are not an integer
+	InvalidInt
+	// InvalidDuration is used for values which are not a duration
+	InvalidDuration
+	// InvalidPath is used for values which are not a valid path
+	InvalidPath
+	// OutOfRange is used for values outside the accepted range
+	OutOfRange
+	// NotAService is used for containers which opted out by setting the
+	// service label to false. These containers may not be registered
+	NotAService
+	// NetworkNotAllowed is used for containers which are not attached to an
+	// allowed docker network. It is not caused by a label, so Label is empty.
+	// These containers may not be registered
+	NetworkNotAllowed
+)
+
+func (k ValidationErrorKind) String() string {
+	switch k {
+	case MissingLabel:
+		return "missing label"
+	case InvalidBool:
+		return "invalid bool"
+	case InvalidInt:
+		return "invalid int"
+	case InvalidDuration:
+		return "invalid duration"
+	case InvalidPath:
+		return "invalid path"
+	case OutOfRange:
+		return "out of range"
+	case NotAService:
+		return "not a service"
+	case NetworkNotAllowed:
+		return "network not allowed"
+	default:
+		return "invalid value"
+	}
+}
+
+// ValidationError describes a label whose value could not be used for the
+// gateway configuration
+type ValidationError struct {
+	// Kind categorizes the reason for the rejection
+	Kind ValidationErrorKind
+	// Label is the key of the label that failed the validation
+	Label string
+	// Value is the raw value of the label
+	Value string
+	// Reason explains why the value has been rejected
+	Reason string
+}
+
+func (e ValidationError) Error() string {
+	if e.Kind == MissingLabel {
+		return fmt.Sprintf("missing label '%s': %s", e.Label, e.Reason)
+	}
+	if e.Kind == NotAService {
+		return fmt.Sprintf("label '%s' is set to '%s': %s", e.Label, e.Value, e.Reason)
+	}
+	if e.Kind == NetworkNotAllowed {
+		return e.Reason
+	}
+	return fmt.Sprintf("invalid value '%s' for label '%s': %s", e.Value, e.Label, e.Reason)
+}
+
+// routeNamePattern matches the names accepted for Kong routes
+var routeNamePattern = regexp.MustCompile(`^[a-zA-Z0-9]+(-[a-zA-Z0-9]+)*$`)
+
+// GatewayConfigurationBuilder converts the labels of a container into a
+// GatewayConfiguration. It keeps track of the labels that have been set
+// explicitly to allow callers to distinguish between a value set by the
+// user and a default value
+type GatewayConfigurationBuilder struct {
+	labels        map[string]string
+	explicit      map[string]bool
+	routeDefaults RouteOptions
+}
+
+// NewGatewayConfigurationBuilder creates a new builder for the supplied
+// container labels
+func NewGatewayConfigurationBuilder(labels map[string]string) *GatewayConfigurationBuilder {
+	return &GatewayConfigurationBuilder{
+		labels:   labels,
+		explicit: make(map[string]bool),
+	}
+}
+
+// WithRouteDefaults sets the route options used for labels which are not set
+// on the container
+func (b *GatewayConfigurationBuilder) WithRouteDefaults(defaults RouteOptions) *GatewayConfigurationBuilder {
+	b.routeDefaults = defaults
+	return b
+}
+
+// Build parses and validates all labels and returns the resulting
+// configuration. Labels with invalid values are reported as validation
+// errors and are treated as if they were not set
+func (b *GatewayConfigurationBuilder) Build() (GatewayConfiguration, []ValidationError) {
+	var config GatewayConfiguration
+	var errs []ValidationError
+	b.explicit = make(map[string]bool)
+
+	config.Enabled = true
+	if value, isSet := b.lookup(ServiceEnabledLabel); isSet {
+		enabled, err := strconv.ParseBool(value)
+		if err != nil {
+			errs = append(errs, ValidationError{InvalidBool, ServiceEnabledLabel, value, "expected a boolean"})
+		} else {
+			config.Enabled = enabled
+			b.explicit[ServiceEnabledLabel] = true
+		}
+	}
+
+	if value, isSet := b.lookup(ServiceNameLabel); isSet {
+		if value == "" {
+			errs = append(errs, ValidationError{InvalidValue, ServiceNameLabel, value, "the service name may not be empty"})
+		} else {
+			config.ServiceName = value
+			b.explicit[ServiceNameLabel] = true
+		}
+	}
+
+	if value, isSet := b.lookup(ServiceIDLabel); isSet {
+		serviceID, err := uuid.Parse(value)
+		if err != nil {
+			errs = append(errs, ValidationError{InvalidValue, ServiceIDLabel, value, "expected a UUID"})
+		} else {
+			id := serviceID.String()
+			config.ServiceID = &id
+			b.explicit[ServiceIDLabel] = true
+		}
+	}
+
+	if value, isSet := b.lookup(ServicePathLabel); isSet {
+		if err := validatePath(value); err != nil {
+			errs = append(errs, ValidationError{InvalidPath, ServicePathLabel, value,
+				err.Error() + " (the path is the route path under which the service is exposed, " +
+					"the path on the service is set with " + ServiceUpstreamPathLabel + ")"})
+		} else {
+			config.Path = value
+			b.explicit[ServicePathLabel] = true
+		}
+	}
+
+	if value, isSet := b.lookup(ServiceUpstreamPathLabel); isSet {
+		if err := validatePath(value); err != nil {
+			errs = append(errs, ValidationError{InvalidPath, ServiceUpstreamPathLabel, value,
+				err.Error() + " (the upstream path is the path requests are forwarded to on the service, " +
+					"the route path is set with " + ServicePathLabel + ")"})
+		} else {
+			config.UpstreamPath = value
+			b.explicit[ServiceUpstreamPathLabel] = true
+		}
+	}
+
+	if value, isSet := b.lookup(ServicePathRegexLabel); isSet {
+		pathRegex, err := normalizePathRegex(value)
+		if err != nil {
+			errs = append(errs, ValidationError{InvalidPath, ServicePathRegexLabel, value, err.Error()})
+		} else {
+			config.PathRegex = pathRegex
+			b.explicit[ServicePathRegexLabel] = true
+		}
+	}
+
+	if value, isSet := b.lookup(ServiceRouteNameLabel); isSet {
+		if !routeNamePattern.MatchString(value) {
+			errs = append(errs, ValidationError{InvalidValue, ServiceRouteNameLabel, value, "the route name may only contain letters, digits and hyphens"})
+		} else {
+			config.RouteName = value
+			b.explicit[ServiceRouteNameLabel] = true
+		}
+	}
+
+	if value, isSet := b.lookup(ServiceUpstreamNameLabel); isSet {
+		if value == "" {
+			errs = append(errs, ValidationError{InvalidValue, ServiceUpstreamNameLabel, value, "the upstream name may not be empty"})
+		} else {
+			config.UpstreamName = value
+			b.explicit[ServiceUpstreamNameLabel] = true
+		}
+	}
+
+	if value, isSet := b.lookup(ServiceHealthcheckLabel); isSet {
+		healthcheck, err := strconv.ParseBool(value)
+		if err != nil {
+			errs = append(errs, ValidationError{InvalidBool, ServiceHealthcheckLabel, value, "expected a boolean"})
+		} else {
+			config.Healthcheck = &healthcheck
+			b.explicit[ServiceHealthcheckLabel] = true
+		}
+	}
+	if config.Healthcheck == nil {
+		healthcheck := true
+		config.Healthcheck = &healthcheck
+	}
+
+	if value, isSet := b.lookup(ServiceRetriesLabel); isSet {
+		retries, err := strconv.Atoi(value)
+		switch {
+		case err != nil:
+			errs = append(errs, ValidationError{InvalidInt, ServiceRetriesLabel, value, "expected an integer"})
+		case retries < 0 || retries > MaxRetries:
+			errs = append(errs, ValidationError{OutOfRange, ServiceRetriesLabel, value, fmt.Sprintf("the number of retries needs to be between 0 and %d", MaxRetries)})
+		default:
+			config.Retries = &retries
+			b.explicit[ServiceRetriesLabel] = true
+		}
+	}
+
+	if value, isSet := b.lookup(ServiceWeightLabel); isSet {
+		weight, err := strconv.Atoi(value)
+		switch {
+		case err != nil:
+			errs = append(errs, ValidationError{InvalidInt, ServiceWeightLabel, value, "expected an integer"})
+		case weight < 0 || weight > 65535:
+			errs = append(errs, ValidationError{OutOfRange, ServiceWeightLabel, value, "the weight needs to be between 0 and 65535"})
+		default:
+			config.Weight = &weight
+			b.explicit[ServiceWeightLabel] = true
+		}
+	}
+	if config.Weight == nil {
+		weight := DefaultTargetWeight
+		config.Weight = &weight
+	}
+
+	if value, isSet := b.lookup(ServiceTimeoutLabel); isSet {
+		timeout, err := time.ParseDuration(value)
+		switch {
+		case err != nil:
+			errs = append(errs, ValidationError{InvalidDuration, ServiceTimeoutLabel, value, "expected a duration (e.g. 30s)"})
+		case timeout < MinTimeout:
+			errs = append(errs, ValidationError{OutOfRange, ServiceTimeoutLabel, value, fmt.Sprintf("the timeout needs to be at least %s", MinTimeout)})
+		default:
+			config.Timeout = &timeout
+			b.explicit[ServiceTimeoutLabel] = true
+		}
+	}
+
+	if value, isSet := b.lookup(ServiceMaxTargetsLabel); isSet {
+		maxTargets, err := strconv.Atoi(value)
+		switch {
+		case err != nil:
+			errs = append(errs, ValidationError{InvalidInt, ServiceMaxTargetsLabel, value, "expected an integer"})
+		case maxTargets < 1:
+			errs = append(errs, ValidationError{OutOfRange, ServiceMaxTargetsLabel, value, "the upstream needs to allow at least one target"})
+		default:
+			config.MaxTargets = &maxTargets
+			b.explicit[ServiceMaxTargetsLabel] = true
+		}
+	}
+
+	if value, isSet := b.lookup(ServiceHTTPSOnlyLabel); isSet {
+		httpsOnly, err := strconv.ParseBool(value)
+		if err != nil {
+			errs = append(errs, ValidationError{InvalidBool, ServiceHTTPSOnlyLabel, value, "expected a boolean"})
+		} else {
+			config.HTTPSOnly = httpsOnly
+			b.explicit[ServiceHTTPSOnlyLabel] = true
+		}
+	}
+
+	config.ServiceDiscovery = DockerServiceDiscovery
+	if value, isSet := b.lookup(ServiceDiscoveryLabel); isSet {
+		switch value {
+		case DockerServiceDiscovery, ConsulServiceDiscovery:
+			config.ServiceDiscovery = value
+			b.explicit[ServiceDiscoveryLabel] = true
+		default:
+			errs = append(errs, ValidationError{InvalidValue, ServiceDiscoveryLabel, value, "expected 'docker' or 'consul'"})
+		}
+	}
+	if config.ServiceDiscovery == ConsulServiceDiscovery {
+		config.ConsulServiceName = config.UpstreamName
+		if value, isSet := b.lookup(ServiceConsulServiceNameLabel); isSet {
+			if value == "" {
+				errs = append(errs, ValidationError{InvalidValue, ServiceConsulServiceNameLabel, value, "the consul service name may not be empty"})
+			} else {
+				config.ConsulServiceName = value
+				b.explicit[ServiceConsulServiceNameLabel] = true
+			}
+		} else if config.ConsulServiceName == "" {
+			errs = append(errs, ValidationError{Kind: MissingLabel, Label: ServiceConsulServiceNameLabel,
+				Reason: "consul service discovery needs the consul service name or the upstream name"})
+		}
+	}
+
+	if value, isSet := b.lookup(ServiceDescriptionLabel); isSet && value != "" {
+		config.Description = value
+		b.explicit[ServiceDescriptionLabel] = true
+	}
+
+	if value, isSet := b.lookup(ServiceDocsURLLabel); isSet {
+		docsURL, err := url.Parse(value)
+		if err != nil || (docsURL.Scheme != "http" && docsURL.Scheme != "https") || docsURL.Host == "" {
+			errs = append(errs, ValidationError{InvalidValue, ServiceDocsURLLabel, value, "expected an absolute http or https url"})
+		} else {
+			config.DocsURL = value
+			b.explicit[ServiceDocsURLLabel] = true
+		}
+	}
+
+	if value, isSet := b.lookup(ServiceOpenAPIPathLabel); isSet {
+		if err := validatePath(value); err != nil {
+			errs = append(errs, ValidationError{InvalidPath, ServiceOpenAPIPathLabel, value, err.Error()})
+		} else {
+			config.OpenAPIPath = value
+			b.explicit[ServiceOpenAPIPathLabel] = true
+		}
+	}
+
+	if value, isSet := b.lookup(ServiceResponseCodeMappingLabel); isSet {
+		mapping, err := parseResponseCodeMapping(value)
+		if err != nil {
+			errs = append(errs, ValidationError{InvalidValue, ServiceResponseCodeMappingLabel, value, err.Error()})
+		} else {
+			config.ResponseCodeMapping = mapping
+			b.explicit[ServiceResponseCodeMappingLabel] = true
+		}
+	}
+
+	if value, isSet := b.lookup(ServiceRateTiersLabel); isSet {
+		rateTiers := SplitList(value)
+		if len(rateTiers) == 0 {
+			errs = append(errs, ValidationError{InvalidValue, ServiceRateTiersLabel, value, "at least one rate tier is required"})
+		} else {
+			config.RateTiers = rateTiers
+			b.explicit[ServiceRateTiersLabel] = true
+		}
+	}
+
+	if value, isSet := b.lookup(ServiceMirrorOfLabel); isSet {
+		switch {
+		case value == "":
+			errs = append(errs, ValidationError{InvalidValue, ServiceMirrorOfLabel, value, "the name of the mirrored service may not be empty"})
+		case value == config.ServiceName:
+			errs = append(errs, ValidationError{InvalidValue, ServiceMirrorOfLabel, value, "a service can not mirror itself"})
+		default:
+			config.MirrorOf = value
+			b.explicit[ServiceMirrorOfLabel] = true
+		}
+	}
+
+	if value, isSet := b.lookup(ServiceMaxRequestSizeLabel); isSet {
+		maxRequestSize, err := strconv.Atoi(value)
+		switch {
+		case err != nil:
+			errs = append(errs, ValidationError{InvalidInt, ServiceMaxRequestSizeLabel, value, "expected an integer"})
+		case maxRequestSize <= 0 || maxRequestSize > MaxRequestSizeLimit:
+			errs = append(errs, ValidationError{OutOfRange, ServiceMaxRequestSizeLabel, value,
+				fmt.Sprintf("the size needs to be between 1 and %d megabytes", MaxRequestSizeLimit)})
+		default:
+			config.MaxRequestSize = &maxRequestSize
+			b.explicit[ServiceMaxRequestSizeLabel] = true
+		}
+	}
+
+	if value, isSet := b.lookup(ServiceProtectedLabel); isSet {
+		protected, err := strconv.ParseBool(value)
+		if err != nil {
+			errs = append(errs, ValidationError{InvalidBool, ServiceProtectedLabel, value, "expected a boolean"})
+		} else {
+			config.Protected = protected
+			b.explicit[ServiceProtectedLabel] = true
+		}
+	}
+
+	errs = append(errs, b.buildRouteOptions(&config)...)
+
+	// the route of a https-only service only accepts https, Kong redirects
+	// requests using http. The configured protocols are replaced, and
+	// reported if they do not include https
+	if config.HTTPSOnly {
+		if protocols := config.RouteOptions.Protocols; protocols != nil && !containsString(protocols, "https") {
+			value, _ := b.lookup(ServiceHTTPSOnlyLabel)
+			errs = append(errs, ValidationError{InvalidValue, ServiceHTTPSOnlyLabel, value,
+				fmt.Sprintf("the route protocols [%s] do not include https", strings.Join(protocols, ", "))})
+		}
+		config.RouteOptions.Protocols = []string{"https"}
+	}
+
+	return config, errs
+}
+
+// containsString reports whether the value is one of the entries
+func containsString(entries []string, value string) bool {
+	for _, entry := range entries {
+		if entry == value {
+			return true
+		}
+	}
+	return false
+}
+
+// buildRouteOptions sets the route options of the configuration from the
+// labels and falls back to the route defaults for labels which are not set
+func (b *GatewayConfigurationBuilder) buildRouteOptions(config *GatewayConfiguration) []ValidationError {
+	var errs []ValidationError
+	config.RouteOptions = b.routeDefaults
+
+	if value, isSet := b.lookup(ServiceStripPathLabel); isSet {
+		stripPath, err := strconv.ParseBool(value)
+		if err != nil {
+			errs = append(errs, ValidationError{InvalidBool, ServiceStripPathLabel, value, "expected a boolean"})
+		} else {
+			config.RouteOptions.StripPath = &stripPath
+			b.explicit[ServiceStripPathLabel] = true
+		}
+	}
+
+	if value, isSet := b.lookup(ServicePreserveHostLabel); isSet {
+		preserveHost, err := strconv.ParseBool(value)
+		if err != nil {
+			errs = append(errs, ValidationError{InvalidBool, ServicePreserveHostLabel, value, "expected a boolean"})
+		} else {
+			config.RouteOptions.PreserveHost = &preserveHost
+			b.explicit[ServicePreserveHostLabel] = true
+		}
+	}
+
+	if value, isSet := b.lookup(ServiceProtocolsLabel); isSet {
+		protocols, err := ParseProtocols(value)
+		if err != nil {
+			errs = append(errs, ValidationError{InvalidValue, ServiceProtocolsLabel, value, err.Error()})
+		} else {
+			config.RouteOptions.Protocols = protocols
+			b.explicit[ServiceProtocolsLabel] = true
+		}
+	}
+
+	if value, isSet := b.lookup(ServiceTagsLabel); isSet {
+		config.RouteOptions.Tags = SplitList(value)
+		b.explicit[ServiceTagsLabel] = true
+	}
+
+	return errs
+}
+
+// ExplicitlySet reports whether the value for the label was set on the
+// container and used in the last call to Build
+func (b *GatewayConfigurationBuilder) ExplicitlySet(label string) bool {
+	return b.explicit[label]
+}
+
+// Source reports where the value of a route option label used in the last
+// call to Build came from
+func (b *GatewayConfigurationBuilder) Source(label string) ValueSource {
+	if b.explicit[label] {
+		return SourceLabel
+	}
+	var hasDefault bool
+	switch label {
+	case ServiceStripPathLabel:
+		hasDefault = b.routeDefaults.StripPath != nil
+	case ServicePreserveHostLabel:
+		hasDefault = b.routeDefaults.PreserveHost != nil
+	case ServiceProtocolsLabel:
+		hasDefault = b.routeDefaults.Protocols != nil
+	case ServiceTagsLabel:
+		hasDefault = b.routeDefaults.Tags != nil
+	}
+	if hasDefault {
+		return SourceDefault
+	}
+	return SourceKongDefault
+}
+
+// lookup returns the trimmed value of a label and whether it is present
+func (b *GatewayConfigurationBuilder) lookup(label string) (string, bool) {
+	value, isSet := b.labels[label]
+	return strings.TrimSpace(value), isSet
+}
+
+// validatePath checks that the value is an absolute URL path
+func validatePath(path string) error {
+	if !strings.HasPrefix(path, "/") {
+		return fmt.Errorf("the path needs to start with '/'")
+	}
+	if strings.ContainsAny(path, "?#") {
+		return fmt.Errorf("the path may not contain a query or fragment")
+	}
+	if _, err := url.ParseRequestURI(path); err != nil {
+		return fmt.Errorf("the path is not a valid url path")
+	}
+	return nil
+}
+
+// normalizePathRegex prepends the "~" marking a regex path in Kong if it is
+// missing and checks that the expression compiles.
+//
+// Kong compiles the expression as PCRE while it is checked with the RE2
+// parser of Go here. Constructs only PCRE supports, like lookarounds and
+// backreferences, are therefore passed to Kong unchecked instead of being
+// rejected
+func normalizePathRegex(pathRegex string) (string, error) {
+	expression := strings.TrimPrefix(pathRegex, "~")
+	if !strings.HasPrefix(expression, "/") {
+		return "", fmt.Errorf("the path regex needs to start with '/' or '~/'")
+	}
+	if _, err := syntax.Parse(expression, syntax.Perl); err != nil {
+		var syntaxError *syntax.Error
+		if !errors.As(err, &syntaxError) ||
+			(syntaxError.Code != syntax.ErrInvalidPerlOp && syntaxError.Code != syntax.ErrInvalidEscape) {
+			return "", fmt.Errorf("the path regex is not a valid regular expression: %w", err)
+		}
+	}
+	return "~" + expression, nil
+}
+
+// parseResponseCodeMapping parses a semicolon-separated list of
+// upstream_code:gateway_code pairs
+func parseResponseCodeMapping(value string) (map[int]int, error) {
+	mapping := make(map[int]int)
+	for _, pair := range strings.Split(value, ";") {
+		pair = strings.TrimSpace(pair)
+		if pair == "" {
+			continue
+		}
+		rawUpstreamCode, rawGatewayCode, found := strings.Cut(pair, ":")
+		if !found {
+			return nil, fmt.Errorf("entry '%s' is not formatted as upstream_code:gateway_code", pair)
+		}
+		upstreamCode, err := parseStatusCode(rawUpstreamCode)
+		if err != nil {
+			return nil, err
+		}
+		gatewayCode, err := parseStatusCode(rawGatewayCode)
+		if err != nil {
+			return nil, err
+		}
+		if _, duplicate := mapping[upstreamCode]; duplicate {
+			return nil, fmt.Errorf("the status code %d is mapped more than once", upstreamCode)
+		}
+		mapping[upstreamCode] = gatewayCode
+	}
+	if len(mapping) == 0 {
+		return nil, fmt.Errorf("the mapping does not contain any entry")
+	}
+	return mapping, nil
+}
+
+// parseStatusCode parses a http status code between 100 and 599
+func parseStatusCode(value string) (int, error) {
+	code, err := strconv.Atoi(strings.TrimSpace(value))
+	if err != nil {
+		return 0, fmt.Errorf("'%s' is not a status code", value)
+	}
+	if code < 100 || code > 599 {
+		return 0, fmt.Errorf("the status code %d is not between 100 and 599", code)
+	}
+	return code, nil
+}
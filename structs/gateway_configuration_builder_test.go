@@ -0,0 +1,343 @@
+package structs
+
+import (
+	"reflect"
+	"testing"
+	"time"
+)
+
+func intPointer(value int) *int {
+	return &value
+}
+
+func TestBuildExplicitlySet(t *testing.T) {
+	tests := []struct {
+		name     string
+		labels   map[string]string
+		explicit bool
+		retries  *int
+	}{
+		{"zero retries", map[string]string{ServiceRetriesLabel: "0"}, true, intPointer(0)},
+		{"absent retries", map[string]string{}, false, nil},
+		{"invalid retries", map[string]string{ServiceRetriesLabel: "often"}, false, nil},
+	}
+	for _, test := range tests {
+		t.Run(test.name, func(t *testing.T) {
+			builder := NewGatewayConfigurationBuilder(test.labels)
+			config, _ := builder.Build()
+			if explicit := builder.ExplicitlySet(ServiceRetriesLabel); explicit != test.explicit {
+				t.Errorf("expected explicitly set to be %v, got %v", test.explicit, explicit)
+			}
+			if (config.Retries == nil) != (test.retries == nil) ||
+				(config.Retries != nil && *config.Retries != *test.retries) {
+				t.Errorf("expected retries %v, got %v", test.retries, config.Retries)
+			}
+		})
+	}
+}
+
+func TestBuildBounds(t *testing.T) {
+	tests := []struct {
+		label     string
+		value     string
+		wantError bool
+	}{
+		{ServiceRetriesLabel, "0", false},
+		{ServiceRetriesLabel, "32767", false},
+		{ServiceRetriesLabel, "32768", true},
+		{ServiceRetriesLabel, "-1", true},
+		{ServiceWeightLabel, "0", false},
+		{ServiceWeightLabel, "65535", false},
+		{ServiceWeightLabel, "65536", true},
+		{ServiceTimeoutLabel, "1ms", false},
+		{ServiceTimeoutLabel, "999us", true},
+		{ServiceTimeoutLabel, "0s", true},
+	}
+	for _, test := range tests {
+		t.Run(test.label+"="+test.value, func(t *testing.T) {
+			builder := NewGatewayConfigurationBuilder(map[string]string{test.label: test.value})
+			_, errs := builder.Build()
+			if !test.wantError {
+				if len(errs) != 0 {
+					t.Errorf("unexpected errors: %v", errs)
+				}
+				return
+			}
+			if len(errs) != 1 || errs[0].Label != test.label {
+				t.Errorf("expected a single error for %s, got %v", test.label, errs)
+			}
+			if builder.ExplicitlySet(test.label) {
+				t.Errorf("rejected value has been marked as explicitly set")
+			}
+		})
+	}
+}
+
+func TestBuildDefaults(t *testing.T) {
+	config, errs := NewGatewayConfigurationBuilder(map[string]string{ServiceTimeoutLabel: "1m30s"}).Build()
+	if len(errs) != 0 {
+		t.Fatalf("unexpected errors: %v", errs)
+	}
+	if config.Weight == nil || *config.Weight != DefaultTargetWeight {
+		t.Errorf("expected the default weight %d, got %v", DefaultTargetWeight, config.Weight)
+	}
+	if config.Healthcheck == nil || !*config.Healthcheck {
+		t.Errorf("expected the healthcheck to be enabled by default, got %v", config.Healthcheck)
+	}
+	if config.Timeout == nil || *config.Timeout != 90*time.Second {
+		t.Errorf("expected a timeout of 1m30s, got %v", config.Timeout)
+	}
+}
+
+func TestBuildServiceID(t *testing.T) {
+	const canonical = "3f6c9a4e-7a8b-4c3d-9e2f-1a2b3c4d5e6f"
+	for _, value := range []string{
+		canonical,
+		"{3F6C9A4E-7A8B-4C3D-9E2F-1A2B3C4D5E6F}",
+		"urn:uuid:3f6c9a4e-7a8b-4c3d-9e2f-1a2b3c4d5e6f",
+		"3F6C9A4E7A8B4C3D9E2F1A2B3C4D5E6F",
+	} {
+		builder := NewGatewayConfigurationBuilder(map[string]string{ServiceIDLabel: value})
+		for run := 0; run < 2; run++ {
+			config, errs := builder.Build()
+			if len(errs) != 0 {
+				t.Fatalf("%s: unexpected errors: %v", value, errs)
+			}
+			if config.ServiceID == nil || *config.ServiceID != canonical {
+				t.Errorf("%s: expected the service id '%s', got %v", value, canonical, config.ServiceID)
+			}
+		}
+	}
+
+	_, errs := NewGatewayConfigurationBuilder(map[string]string{ServiceIDLabel: "users-1"}).Build()
+	if len(errs) != 1 || errs[0].Kind != InvalidValue || errs[0].Label != ServiceIDLabel {
+		t.Errorf("expected a single %s error for %s, got %v", InvalidValue, ServiceIDLabel, errs)
+	}
+}
+
+func TestNormalizePathRegex(t *testing.T) {
+	tests := []struct {
+		name      string
+		pathRegex string
+		expected  string
+		wantError bool
+	}{
+		{"without tilde", `/api/v\d+/users`, `~/api/v\d+/users`, false},
+		{"with tilde", `~/api/v\d+/users`, `~/api/v\d+/users`, false},
+		{"lookahead", `~/api/(?!internal)[a-z]+`, `~/api/(?!internal)[a-z]+`, false},
+		{"backreference", `/(\w+)/\1`, `~/(\w+)/\1`, false},
+		{"missing slash", `api/[a-z]+`, "", true},
+		{"missing slash after tilde", `~api/[a-z]+`, "", true},
+		{"unbalanced parenthesis", `~/api/(users`, "", true},
+		{"invalid repetition", `/api/**`, "", true},
+	}
+	for _, test := range tests {
+		t.Run(test.name, func(t *testing.T) {
+			pathRegex, err := normalizePathRegex(test.pathRegex)
+			if test.wantError {
+				if err == nil {
+					t.Fatalf("expected an error for '%s', got '%s'", test.pathRegex, pathRegex)
+				}
+				return
+			}
+			if err != nil {
+				t.Fatalf("unexpected error: %v", err)
+			}
+			if pathRegex != test.expected {
+				t.Errorf("expected '%s', got '%s'", test.expected, pathRegex)
+			}
+		})
+	}
+}
+
+func TestBuildHTTPSOnlyProtocols(t *testing.T) {
+	tests := []struct {
+		name      string
+		protocols string
+		defaults  RouteOptions
+		wantError bool
+	}{
+		{"kong default protocols", "", RouteOptions{}, false},
+		{"protocols include https", "http,https", RouteOptions{}, false},
+		{"protocols exclude https", "http", RouteOptions{}, true},
+		{"default protocols exclude https", "", RouteOptions{Protocols: []string{"http"}}, true},
+		{"label overrides default protocols", "https", RouteOptions{Protocols: []string{"http"}}, false},
+	}
+	for _, test := range tests {
+		t.Run(test.name, func(t *testing.T) {
+			labels := map[string]string{ServiceHTTPSOnlyLabel: "true"}
+			if test.protocols != "" {
+				labels[ServiceProtocolsLabel] = test.protocols
+			}
+			config, errs := NewGatewayConfigurationBuilder(labels).WithRouteDefaults(test.defaults).Build()
+			if !config.HTTPSOnly {
+				t.Errorf("https-only has been dropped")
+			}
+			if protocols := config.RouteOptions.Protocols; len(protocols) != 1 || protocols[0] != "https" {
+				t.Errorf("expected the protocols [https], got %v", protocols)
+			}
+			if !test.wantError {
+				if len(errs) != 0 {
+					t.Errorf("unexpected errors: %v", errs)
+				}
+				return
+			}
+			if len(errs) != 1 || errs[0].Label != ServiceHTTPSOnlyLabel || errs[0].Kind != InvalidValue {
+				t.Errorf("expected a single %s error for %s, got %v", InvalidValue, ServiceHTTPSOnlyLabel, errs)
+			}
+		})
+	}
+}
+
+func TestBuildSource(t *testing.T) {
+	stripPath := true
+	defaults := RouteOptions{StripPath: &stripPath, Tags: []string{"managed"}}
+	builder := NewGatewayConfigurationBuilder(map[string]string{
+		ServiceProtocolsLabel: "https",
+		ServiceTagsLabel:      "orders",
+		ServiceStripPathLabel: "no",
+	}).WithRouteDefaults(defaults)
+	if _, errs := builder.Build(); len(errs) != 1 || errs[0].Label != ServiceStripPathLabel {
+		t.Fatalf("expected a single error for %s, got %v", ServiceStripPathLabel, errs)
+	}
+
+	tests := []struct {
+		label    string
+		expected ValueSource
+	}{
+		{ServiceProtocolsLabel, SourceLabel},
+		{ServiceTagsLabel, SourceLabel},
+		{ServiceStripPathLabel, SourceDefault},
+		{ServicePreserveHostLabel, SourceKongDefault},
+	}
+	for _, test := range tests {
+		if source := builder.Source(test.label); source != test.expected {
+			t.Errorf("%s: expected the source %s, got %s", test.label, test.expected, source)
+		}
+	}
+}
+
+func TestBuildConsulServiceName(t *testing.T) {
+	tests := []struct {
+		name      string
+		labels    map[string]string
+		expected  string
+		wantError bool
+		kind      ValidationErrorKind
+	}{
+		{
+			name:     "consul service name label",
+			labels:   map[string]string{ServiceConsulServiceNameLabel: "users-api", ServiceUpstreamNameLabel: "users"},
+			expected: "users-api",
+		},
+		{
+			name:     "upstream name fallback",
+			labels:   map[string]string{ServiceUpstreamNameLabel: "users"},
+			expected: "users",
+		},
+		{
+			name:      "neither name",
+			labels:    map[string]string{},
+			wantError: true,
+			kind:      MissingLabel,
+		},
+		{
+			name:      "empty consul service name",
+			labels:    map[string]string{ServiceConsulServiceNameLabel: " "},
+			wantError: true,
+			kind:      InvalidValue,
+		},
+	}
+	for _, test := range tests {
+		t.Run(test.name, func(t *testing.T) {
+			test.labels[ServiceDiscoveryLabel] = ConsulServiceDiscovery
+			config, errs := NewGatewayConfigurationBuilder(test.labels).Build()
+			if !test.wantError {
+				if len(errs) != 0 {
+					t.Fatalf("unexpected errors: %v", errs)
+				}
+				if config.ConsulServiceName != test.expected {
+					t.Errorf("expected consul service name '%s', got '%s'", test.expected, config.ConsulServiceName)
+				}
+				return
+			}
+			if len(errs) != 1 || errs[0].Kind != test.kind || errs[0].Label != ServiceConsulServiceNameLabel {
+				t.Errorf("expected a single %s error for %s, got %v", test.kind, ServiceConsulServiceNameLabel, errs)
+			}
+		})
+	}
+}
+
+func TestParseResponseCodeMapping(t *testing.T) {
+	tests := []struct {
+		name      string
+		value     string
+		expected  map[int]int
+		wantError bool
+	}{
+		{"single entry", "404:410", map[int]int{404: 410}, false},
+		{"multiple entries", " 500:503 ; 401:403;", map[int]int{500: 503, 401: 403}, false},
+		{"bounds", "100:599;599:100", map[int]int{100: 599, 599: 100}, false},
+		{"upstream code below 100", "99:200", nil, true},
+		{"gateway code below 100", "404:0", nil, true},
+		{"upstream code above 599", "600:500", nil, true},
+		{"gateway code above 599", "500:1000", nil, true},
+		{"not a number", "404:gone", nil, true},
+		{"missing separator", "404", nil, true},
+		{"duplicate upstream code", "404:410;404:400", nil, true},
+		{"empty", " ; ", nil, true},
+	}
+	for _, test := range tests {
+		t.Run(test.name, func(t *testing.T) {
+			mapping, err := parseResponseCodeMapping(test.value)
+			if test.wantError {
+				if err == nil {
+					t.Errorf("expected an error for '%s', got %v", test.value, mapping)
+				}
+				return
+			}
+			if err != nil {
+				t.Fatalf("unexpected error: %v", err)
+			}
+			if !reflect.DeepEqual(mapping, test.expected) {
+				t.Errorf("expected %v, got %v", test.expected, mapping)
+			}
+		})
+	}
+}
+
+func TestBuildMaxRequestSize(t *testing.T) {
+	tests := []struct {
+		value     string
+		expected  int
+		wantError bool
+		kind      ValidationErrorKind
+	}{
+		{"0", 0, true, OutOfRange},
+		{"1", 1, false, 0},
+		{"2048", 2048, false, 0},
+		{"2049", 0, true, OutOfRange},
+		{"-1", 0, true, OutOfRange},
+		{"1.5", 0, true, InvalidInt},
+	}
+	for _, test := range tests {
+		t.Run(test.value, func(t *testing.T) {
+			config, errs := NewGatewayConfigurationBuilder(map[string]string{ServiceMaxRequestSizeLabel: test.value}).Build()
+			if test.wantError {
+				if len(errs) != 1 || errs[0].Kind != test.kind || errs[0].Label != ServiceMaxRequestSizeLabel {
+					t.Errorf("expected a single %s error for %s, got %v", test.kind, ServiceMaxRequestSizeLabel, errs)
+				}
+				if config.MaxRequestSize != nil {
+					t.Errorf("expected no maximal request size, got %d", *config.MaxRequestSize)
+				}
+				return
+			}
+			if len(errs) != 0 {
+				t.Fatalf("unexpected errors: %v", errs)
+			}
+			if config.MaxRequestSize == nil || *config.MaxRequestSize != test.expected {
+				t.Errorf("expected a maximal request size of %d, got %v", test.expected, config.MaxRequestSize)
+			}
+		})
+	}
+}
@@ -0,0 +1,16 @@
+package structs
+
+import "strings"
+
+// SplitList splits a comma-separated list as used by list labels and
+// environment variables, e.g. "a, b,,c". The entries are trimmed and empty
+// entries are dropped
+func SplitList(value string) []string {
+	var entries []string
+	for _, entry := range strings.Split(value, ",") {
+		if entry = strings.TrimSpace(entry); entry != "" {
+			entries = append(entries, entry)
+		}
+	}
+	return entries
+}
@@ -0,0 +1,23 @@
+package structs
+
+import (
+	"reflect"
+	"testing"
+)
+
+func TestSplitList(t *testing.T) {
+	tests := []struct {
+		value    string
+		expected []string
+	}{
+		{"", nil},
+		{" , ", nil},
+		{"dev", []string{"dev"}},
+		{" dev, ,staging ,", []string{"dev", "staging"}},
+	}
+	for _, test := range tests {
+		if entries := SplitList(test.value); !reflect.DeepEqual(entries, test.expected) {
+			t.Errorf("SplitList(%q): expected %v, got %v", test.value, test.expected, entries)
+		}
+	}
+}
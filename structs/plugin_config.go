@@ -0,0 +1,116 @@
+package structs
+
+import (
+	"encoding/json"
+	"fmt"
+	"math"
+	"strconv"
+	"strings"
+)
+
+// PluginConfig contains the configuration of a Kong plugin as it is sent to
+// the admin API
+type PluginConfig map[string]interface{}
+
+// UnmarshalLabel parses the value of a plugin label. The value is either a
+// JSON object, e.g.
+//
+//	{"second":10,"minute":100}
+//
+// or a list of key=value pairs separated by semicolons, e.g.
+//
+//	second=10;minute=100;policy=local
+//
+// Values in the pair notation are converted to integers, floats and booleans
+// if possible. A value wrapped in double quotes is always kept as string.
+//
+// Both notations produce the same types: numbers written without a fraction
+// or exponent are decoded as int and all other numbers as float64, also in
+// nested JSON objects and arrays
+func (c *PluginConfig) UnmarshalLabel(s string) error {
+	s = strings.TrimSpace(s)
+	if s == "" {
+		return fmt.Errorf("empty plugin configuration")
+	}
+	if strings.HasPrefix(s, "{") {
+		decoder := json.NewDecoder(strings.NewReader(s))
+		decoder.UseNumber()
+		var config map[string]interface{}
+		if err := decoder.Decode(&config); err != nil {
+			return fmt.Errorf("invalid json plugin configuration: %w", err)
+		}
+		if decoder.More() {
+			return fmt.Errorf("invalid json plugin configuration: unexpected data after the object")
+		}
+		*c = normalizeJSONValue(config).(map[string]interface{})
+		return nil
+	}
+
+	config := make(PluginConfig)
+	for _, pair := range strings.Split(s, ";") {
+		pair = strings.TrimSpace(pair)
+		if pair == "" {
+			continue
+		}
+		key, value, found := strings.Cut(pair, "=")
+		key = strings.TrimSpace(key)
+		if !found || key == "" {
+			return fmt.Errorf("invalid plugin configuration entry '%s': expected key=value", pair)
+		}
+		if _, duplicate := config[key]; duplicate {
+			return fmt.Errorf("duplicate plugin configuration key '%s'", key)
+		}
+		config[key] = parseLabelValue(strings.TrimSpace(value))
+	}
+	if len(config) == 0 {
+		return fmt.Errorf("empty plugin configuration")
+	}
+	*c = config
+	return nil
+}
+
+// parseLabelValue converts a value of the key=value notation into the type it
+// represents
+func parseLabelValue(value string) interface{} {
+	if unquoted, err := strconv.Unquote(value); err == nil && strings.HasPrefix(value, `"`) {
+		return unquoted
+	}
+	if integer, err := strconv.Atoi(value); err == nil {
+		return integer
+	}
+	if float, err := strconv.ParseFloat(value, 64); err == nil && !math.IsInf(float, 0) && !math.IsNaN(float) {
+		return float
+	}
+	switch strings.ToLower(value) {
+	case "true":
+		return true
+	case "false":
+		return false
+	}
+	return value
+}
+
+// normalizeJSONValue replaces the json.Number values of a decoded JSON value
+// with an int if the number is an integer literal and a float64 otherwise
+func normalizeJSONValue(value interface{}) interface{} {
+	switch value := value.(type) {
+	case json.Number:
+		if integer, err := strconv.Atoi(value.String()); err == nil {
+			return integer
+		}
+		float, _ := value.Float64()
+		return float
+	case map[string]interface{}:
+		for key, entry := range value {
+			value[key] = normalizeJSONValue(entry)
+		}
+		return value
+	case []interface{}:
+		for index, entry := range value {
+			value[index] = normalizeJSONValue(entry)
+		}
+		return value
+	default:
+		return value
+	}
+}
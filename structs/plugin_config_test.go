@@ -0,0 +1,88 @@
+package structs
+
+import (
+	"reflect"
+	"testing"
+)
+
+func TestPluginConfigUnmarshalLabel(t *testing.T) {
+	tests := []struct {
+		name     string
+		value    string
+		expected PluginConfig
+	}{
+		{
+			name:     "json",
+			value:    `{"second":10,"minute":100,"policy":"local"}`,
+			expected: PluginConfig{"second": 10, "minute": 100, "policy": "local"},
+		},
+		{
+			name:     "pairs",
+			value:    "second=10;minute=100;policy=local",
+			expected: PluginConfig{"second": 10, "minute": 100, "policy": "local"},
+		},
+		{
+			name:     "mixed pair values",
+			value:    ` limit = 5 ; ratio=0.5;enabled=true;hide=FALSE;name="10";path=/api; `,
+			expected: PluginConfig{"limit": 5, "ratio": 0.5, "enabled": true, "hide": false, "name": "10", "path": "/api"},
+		},
+		{
+			name:  "nested json numbers",
+			value: `{"ratio":0.5,"large":1e3,"codes":[200,201],"limits":{"minute":60}}`,
+			expected: PluginConfig{
+				"ratio":  0.5,
+				"large":  float64(1000),
+				"codes":  []interface{}{200, 201},
+				"limits": map[string]interface{}{"minute": 60},
+			},
+		},
+	}
+	for _, test := range tests {
+		t.Run(test.name, func(t *testing.T) {
+			var config PluginConfig
+			if err := config.UnmarshalLabel(test.value); err != nil {
+				t.Fatalf("unexpected error: %v", err)
+			}
+			if !reflect.DeepEqual(config, test.expected) {
+				t.Errorf("expected %#v, got %#v", test.expected, config)
+			}
+		})
+	}
+}
+
+func TestPluginConfigUnmarshalLabelNotationsMatch(t *testing.T) {
+	var jsonConfig, pairConfig PluginConfig
+	if err := jsonConfig.UnmarshalLabel(`{"second":10,"ratio":0.25,"enabled":true}`); err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	if err := pairConfig.UnmarshalLabel("second=10;ratio=0.25;enabled=true"); err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	if !reflect.DeepEqual(jsonConfig, pairConfig) {
+		t.Errorf("json notation produced %#v, pair notation produced %#v", jsonConfig, pairConfig)
+	}
+}
+
+func TestPluginConfigUnmarshalLabelInvalid(t *testing.T) {
+	tests := []struct {
+		name  string
+		value string
+	}{
+		{"empty", "  "},
+		{"invalid json", `{"second":10,}`},
+		{"unterminated json", `{"second":10`},
+		{"trailing json data", `{"second":10} {"minute":100}`},
+		{"missing value separator", "second=10;minute"},
+		{"missing key", "=10"},
+		{"duplicate key", "second=10;second=20"},
+		{"only separators", ";;"},
+	}
+	for _, test := range tests {
+		t.Run(test.name, func(t *testing.T) {
+			var config PluginConfig
+			if err := config.UnmarshalLabel(test.value); err == nil {
+				t.Errorf("expected an error for '%s', got %#v", test.value, config)
+			}
+		})
+	}
+}
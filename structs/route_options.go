@@ -0,0 +1,64 @@
+package structs
+
+import "fmt"
+
+// Labels configuring the Kong route of a service
+const (
+	ServiceStripPathLabel    = "wisdom-oss.service.strip-path"
+	ServicePreserveHostLabel = "wisdom-oss.service.preserve-host"
+	ServiceProtocolsLabel    = "wisdom-oss.service.protocols"
+	ServiceTagsLabel         = "wisdom-oss.service.tags"
+)
+
+// RouteOptions contains the options of a Kong route. Options which are nil
+// are not sent to Kong, so the Kong default applies
+type RouteOptions struct {
+	StripPath    *bool
+	PreserveHost *bool
+	Protocols    []string
+	Tags         []string
+}
+
+// ValueSource describes where the value of an option came from
+type ValueSource int
+
+const (
+	// SourceKongDefault is used for options left to the default of Kong
+	SourceKongDefault ValueSource = iota
+	// SourceDefault is used for options set by the configured defaults
+	SourceDefault
+	// SourceLabel is used for options set by a container label
+	SourceLabel
+)
+
+func (s ValueSource) String() string {
+	switch s {
+	case SourceDefault:
+		return "default"
+	case SourceLabel:
+		return "label"
+	default:
+		return "kong"
+	}
+}
+
+// routeProtocols are the protocols accepted by Kong routes
+var routeProtocols = map[string]bool{
+	"http": true, "https": true, "grpc": true, "grpcs": true,
+	"tcp": true, "tls": true, "udp": true,
+}
+
+// ParseProtocols splits a comma-separated list of route protocols and checks
+// that Kong supports each of them
+func ParseProtocols(value string) ([]string, error) {
+	protocols := SplitList(value)
+	if len(protocols) == 0 {
+		return nil, fmt.Errorf("at least one protocol is required")
+	}
+	for _, protocol := range protocols {
+		if !routeProtocols[protocol] {
+			return nil, fmt.Errorf("unsupported protocol '%s'", protocol)
+		}
+	}
+	return protocols, nil
+}
@@ -0,0 +1,65 @@
+package utils
+
+import (
+	"encoding/json"
+	"strings"
+
+	"gateway-service-watcher/structs"
+)
+
+// ComposeProjectLabel is set by docker compose on every container to the
+// name of the project the container belongs to
+const ComposeProjectLabel = "com.docker.compose.project"
+
+// ComposeProfilesLabel is set by docker compose on containers of services
+// which are assigned to profiles
+const ComposeProfilesLabel = "com.docker.compose.profiles"
+
+// ComposeProjectTagPrefix is the prefix of the tag marking the Kong objects
+// registered for containers of a compose project
+const ComposeProjectTagPrefix = "compose-project:"
+
+// MatchesComposeProject reports whether a container belongs to the compose
+// project. Without a project every container matches
+func MatchesComposeProject(labels map[string]string, project string) bool {
+	if project == "" {
+		return true
+	}
+	return strings.TrimSpace(labels[ComposeProjectLabel]) == project
+}
+
+// ComposeProjectTag returns the tag for the Kong objects of a compose project
+func ComposeProjectTag(project string) string {
+	return ComposeProjectTagPrefix + project
+}
+
+// containerProfiles extracts the profiles from the container labels. The
+// label contains a json array, but a plain comma-separated value is
+// accepted as well
+func containerProfiles(labels map[string]string) []string {
+	value := strings.TrimSpace(labels[ComposeProfilesLabel])
+	if value == "" {
+		return nil
+	}
+	var profiles []string
+	if err := json.Unmarshal([]byte(value), &profiles); err == nil {
+		return profiles
+	}
+	return structs.SplitList(value)
+}
+
+// MatchesComposeProfiles reports whether a container belongs to at least one
+// of the allowed profiles. Without allowed profiles every container matches
+func MatchesComposeProfiles(labels map[string]string, allowedProfiles []string) bool {
+	if len(allowedProfiles) == 0 {
+		return true
+	}
+	for _, profile := range containerProfiles(labels) {
+		for _, allowedProfile := range allowedProfiles {
+			if profile == allowedProfile {
+				return true
+			}
+		}
+	}
+	return false
+}
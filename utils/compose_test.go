@@ -0,0 +1,61 @@
+package utils
+
+import (
+	"testing"
+)
+
+func TestMatchesComposeProject(t *testing.T) {
+	tests := []struct {
+		name     string
+		labels   map[string]string
+		project  string
+		expected bool
+	}{
+		{"no project", map[string]string{ComposeProjectLabel: "wisdom"}, "", true},
+		{"no project without label", map[string]string{}, "", true},
+		{"same project", map[string]string{ComposeProjectLabel: "wisdom"}, "wisdom", true},
+		{"other project", map[string]string{ComposeProjectLabel: "monitoring"}, "wisdom", false},
+		{"container without project", map[string]string{}, "wisdom", false},
+		{"case sensitive", map[string]string{ComposeProjectLabel: "Wisdom"}, "wisdom", false},
+	}
+	for _, test := range tests {
+		t.Run(test.name, func(t *testing.T) {
+			if matches := MatchesComposeProject(test.labels, test.project); matches != test.expected {
+				t.Errorf("expected %v, got %v", test.expected, matches)
+			}
+		})
+	}
+}
+
+func TestComposeProjectTag(t *testing.T) {
+	if tag := ComposeProjectTag("wisdom"); tag != "compose-project:wisdom" {
+		t.Errorf("unexpected tag '%s'", tag)
+	}
+}
+
+func TestMatchesComposeProfiles(t *testing.T) {
+	tests := []struct {
+		name            string
+		labels          map[string]string
+		allowedProfiles []string
+		expected        bool
+	}{
+		{"no allowed profiles", map[string]string{ComposeProfilesLabel: `["dev"]`}, nil, true},
+		{"no allowed profiles without label", map[string]string{}, nil, true},
+		{"container without profiles", map[string]string{}, []string{"dev"}, false},
+		{"empty label", map[string]string{ComposeProfilesLabel: " "}, []string{"dev"}, false},
+		{"json single match", map[string]string{ComposeProfilesLabel: `["dev"]`}, []string{"dev"}, true},
+		{"json intersection", map[string]string{ComposeProfilesLabel: `["debug","staging"]`}, []string{"dev", "staging"}, true},
+		{"json disjoint", map[string]string{ComposeProfilesLabel: `["debug","test"]`}, []string{"dev", "staging"}, false},
+		{"comma-separated intersection", map[string]string{ComposeProfilesLabel: "debug, staging"}, []string{"staging"}, true},
+		{"comma-separated disjoint", map[string]string{ComposeProfilesLabel: "debug,test"}, []string{"staging"}, false},
+		{"case sensitive", map[string]string{ComposeProfilesLabel: `["Dev"]`}, []string{"dev"}, false},
+	}
+	for _, test := range tests {
+		t.Run(test.name, func(t *testing.T) {
+			if matches := MatchesComposeProfiles(test.labels, test.allowedProfiles); matches != test.expected {
+				t.Errorf("expected %v, got %v", test.expected, matches)
+			}
+		})
+	}
+}
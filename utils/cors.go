@@ -0,0 +1,48 @@
+package utils
+
+import (
+	"strconv"
+	"strings"
+
+	"gateway-service-watcher/structs"
+)
+
+// Labels configuring the CORS plugin of a service
+const (
+	CORSOriginsLabel     = "wisdom-oss.service.cors.origins"
+	CORSCredentialsLabel = "wisdom-oss.service.cors.credentials"
+)
+
+// ValidateCORSConfig checks the CORS labels of a container for combinations
+// browsers reject. Allowing credentials together with the wildcard origin is
+// such a combination. The returned error is a structs.ValidationError
+func ValidateCORSConfig(labels map[string]string) error {
+	rawCredentials, isSet := labels[CORSCredentialsLabel]
+	if !isSet {
+		return nil
+	}
+	credentials, err := strconv.ParseBool(strings.TrimSpace(rawCredentials))
+	if err != nil {
+		return structs.ValidationError{
+			Kind:   structs.InvalidBool,
+			Label:  CORSCredentialsLabel,
+			Value:  rawCredentials,
+			Reason: "expected a boolean",
+		}
+	}
+	if !credentials {
+		return nil
+	}
+	for _, origin := range structs.SplitList(labels[CORSOriginsLabel]) {
+		if origin == "*" {
+			return structs.ValidationError{
+				Kind:  structs.InvalidValue,
+				Label: CORSOriginsLabel,
+				Value: labels[CORSOriginsLabel],
+				Reason: "browsers reject all origins while " + CORSCredentialsLabel + " is enabled: " +
+					"list the allowed origins explicitly or disable credentials",
+			}
+		}
+	}
+	return nil
+}
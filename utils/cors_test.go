@@ -0,0 +1,67 @@
+package utils
+
+import (
+	"errors"
+	"testing"
+
+	"gateway-service-watcher/structs"
+)
+
+func TestValidateCORSConfig(t *testing.T) {
+	tests := []struct {
+		name      string
+		labels    map[string]string
+		wantError bool
+		kind      structs.ValidationErrorKind
+		label     string
+	}{
+		{
+			name:   "no cors labels",
+			labels: map[string]string{},
+		},
+		{
+			name:      "wildcard with credentials",
+			labels:    map[string]string{CORSOriginsLabel: "https://a.example.com, *", CORSCredentialsLabel: "true"},
+			wantError: true,
+			kind:      structs.InvalidValue,
+			label:     CORSOriginsLabel,
+		},
+		{
+			name:   "explicit origins with credentials",
+			labels: map[string]string{CORSOriginsLabel: "https://a.example.com,https://b.example.com", CORSCredentialsLabel: "true"},
+		},
+		{
+			name:   "wildcard without credentials",
+			labels: map[string]string{CORSOriginsLabel: "*"},
+		},
+		{
+			name:   "wildcard with disabled credentials",
+			labels: map[string]string{CORSOriginsLabel: "*", CORSCredentialsLabel: "false"},
+		},
+		{
+			name:      "invalid credentials",
+			labels:    map[string]string{CORSOriginsLabel: "*", CORSCredentialsLabel: "sometimes"},
+			wantError: true,
+			kind:      structs.InvalidBool,
+			label:     CORSCredentialsLabel,
+		},
+	}
+	for _, test := range tests {
+		t.Run(test.name, func(t *testing.T) {
+			err := ValidateCORSConfig(test.labels)
+			if !test.wantError {
+				if err != nil {
+					t.Errorf("unexpected error: %v", err)
+				}
+				return
+			}
+			var validationError structs.ValidationError
+			if !errors.As(err, &validationError) {
+				t.Fatalf("expected a validation error, got %v", err)
+			}
+			if validationError.Kind != test.kind || validationError.Label != test.label {
+				t.Errorf("expected %s for %s, got %s for %s", test.kind, test.label, validationError.Kind, validationError.Label)
+			}
+		})
+	}
+}
@@ -0,0 +1,87 @@
+package utils
+
+import (
+	"reflect"
+	"time"
+
+	"github.com/rs/zerolog"
+
+	"gateway-service-watcher/structs"
+)
+
+// ConfigChange describes a field whose value differs between two gateway
+// configurations. Pointer values are dereferenced, unset pointers are nil
+type ConfigChange struct {
+	Field string      `json:"field"`
+	Old   interface{} `json:"old"`
+	New   interface{} `json:"new"`
+}
+
+// DiffGatewayConfig returns the names of the fields which differ between the
+// two configurations. Pointer fields are compared by the values they point
+// to. An empty result means that the configurations are equal
+func DiffGatewayConfig(previous, current structs.GatewayConfiguration) []string {
+	var changedFields []string
+	for _, change := range DiffGatewayConfigValues(previous, current) {
+		changedFields = append(changedFields, change.Field)
+	}
+	return changedFields
+}
+
+// DiffGatewayConfigValues returns the changed fields together with their old
+// and new values
+func DiffGatewayConfigValues(previous, current structs.GatewayConfiguration) []ConfigChange {
+	var changes []ConfigChange
+	previousValue := reflect.ValueOf(previous)
+	currentValue := reflect.ValueOf(current)
+	configType := previousValue.Type()
+	for i := 0; i < configType.NumField(); i++ {
+		previousField := previousValue.Field(i).Interface()
+		currentField := currentValue.Field(i).Interface()
+		if !reflect.DeepEqual(previousField, currentField) {
+			changes = append(changes, ConfigChange{
+				Field: configType.Field(i).Name,
+				Old:   dereference(previousValue.Field(i)),
+				New:   dereference(currentValue.Field(i)),
+			})
+		}
+	}
+	return changes
+}
+
+// LogConfigChanges writes the changes of the configuration of a service as a
+// single info entry. Nothing is logged if there are no changes
+func LogConfigChanges(logger *zerolog.Logger, serviceName string, changes []ConfigChange) {
+	if len(changes) == 0 {
+		return
+	}
+	diff := zerolog.Arr()
+	changedFields := make([]string, 0, len(changes))
+	for _, change := range changes {
+		diff.Dict(zerolog.Dict().
+			Str("field", change.Field).
+			Interface("old", change.Old).
+			Interface("new", change.New))
+		changedFields = append(changedFields, change.Field)
+	}
+	logger.Info().
+		Str("service", serviceName).
+		Strs("changedFields", changedFields).
+		Array("diff", diff).
+		Msg("gateway configuration changed")
+}
+
+// dereference returns the value a pointer points to or nil for nil pointers.
+// Durations are returned in their string form to keep them readable in logs
+func dereference(value reflect.Value) interface{} {
+	if value.Kind() == reflect.Ptr {
+		if value.IsNil() {
+			return nil
+		}
+		value = value.Elem()
+	}
+	if duration, isDuration := value.Interface().(time.Duration); isDuration {
+		return duration.String()
+	}
+	return value.Interface()
+}
@@ -0,0 +1,147 @@
+package utils
+
+import (
+	"bytes"
+	"reflect"
+	"testing"
+	"time"
+
+	"github.com/rs/zerolog"
+
+	"gateway-service-watcher/structs"
+)
+
+func intPointer(value int) *int {
+	return &value
+}
+
+func TestDiffGatewayConfig(t *testing.T) {
+	timeout := 30 * time.Second
+	tests := []struct {
+		name     string
+		previous structs.GatewayConfiguration
+		current  structs.GatewayConfiguration
+		expected []string
+	}{
+		{"empty", structs.GatewayConfiguration{}, structs.GatewayConfiguration{}, nil},
+		{
+			name:     "distinct pointers to equal values",
+			previous: structs.GatewayConfiguration{Retries: intPointer(3), Timeout: &timeout},
+			current:  structs.GatewayConfiguration{Retries: intPointer(3), Timeout: &timeout},
+			expected: nil,
+		},
+		{
+			name:     "pointers to different values",
+			previous: structs.GatewayConfiguration{Retries: intPointer(3)},
+			current:  structs.GatewayConfiguration{Retries: intPointer(5)},
+			expected: []string{"Retries"},
+		},
+		{
+			name:     "nil and set pointer",
+			previous: structs.GatewayConfiguration{},
+			current:  structs.GatewayConfiguration{Weight: intPointer(0)},
+			expected: []string{"Weight"},
+		},
+		{
+			name:     "set and nil pointer",
+			previous: structs.GatewayConfiguration{MaxTargets: intPointer(2)},
+			current:  structs.GatewayConfiguration{},
+			expected: []string{"MaxTargets"},
+		},
+		{
+			name:     "disabled service",
+			previous: structs.GatewayConfiguration{Enabled: true},
+			current:  structs.GatewayConfiguration{Enabled: false},
+			expected: []string{"Enabled"},
+		},
+		{
+			name:     "nested route options",
+			previous: structs.GatewayConfiguration{RouteOptions: structs.RouteOptions{Protocols: []string{"http", "https"}}},
+			current:  structs.GatewayConfiguration{RouteOptions: structs.RouteOptions{Protocols: []string{"https"}}},
+			expected: []string{"RouteOptions"},
+		},
+		{
+			name:     "equal nested route options",
+			previous: structs.GatewayConfiguration{RouteOptions: structs.RouteOptions{Tags: []string{"managed"}}},
+			current:  structs.GatewayConfiguration{RouteOptions: structs.RouteOptions{Tags: []string{"managed"}}},
+			expected: nil,
+		},
+		{
+			name:     "map entry changed",
+			previous: structs.GatewayConfiguration{ResponseCodeMapping: map[int]int{404: 410}},
+			current:  structs.GatewayConfiguration{ResponseCodeMapping: map[int]int{404: 404}},
+			expected: []string{"ResponseCodeMapping"},
+		},
+		{
+			name:     "equal maps",
+			previous: structs.GatewayConfiguration{ResponseCodeMapping: map[int]int{404: 410, 500: 503}},
+			current:  structs.GatewayConfiguration{ResponseCodeMapping: map[int]int{500: 503, 404: 410}},
+			expected: nil,
+		},
+		{
+			name:     "several fields in declaration order",
+			previous: structs.GatewayConfiguration{ServiceName: "users", Path: "/users"},
+			current:  structs.GatewayConfiguration{ServiceName: "accounts", Path: "/accounts"},
+			expected: []string{"ServiceName", "Path"},
+		},
+	}
+	for _, test := range tests {
+		t.Run(test.name, func(t *testing.T) {
+			if changedFields := DiffGatewayConfig(test.previous, test.current); !reflect.DeepEqual(changedFields, test.expected) {
+				t.Errorf("expected %v, got %v", test.expected, changedFields)
+			}
+		})
+	}
+}
+
+func TestDiffGatewayConfigValues(t *testing.T) {
+	previousTimeout := 30 * time.Second
+	currentTimeout := 90 * time.Second
+	changes := DiffGatewayConfigValues(
+		structs.GatewayConfiguration{Retries: intPointer(3), Timeout: &previousTimeout},
+		structs.GatewayConfiguration{Timeout: &currentTimeout},
+	)
+	expected := []ConfigChange{
+		{Field: "Retries", Old: 3, New: nil},
+		{Field: "Timeout", Old: "30s", New: "1m30s"},
+	}
+	if !reflect.DeepEqual(changes, expected) {
+		t.Errorf("expected %+v, got %+v", expected, changes)
+	}
+}
+
+func TestLogConfigChanges(t *testing.T) {
+	var output bytes.Buffer
+	logger := zerolog.New(&output)
+
+	previousTimeout := 30 * time.Second
+	currentTimeout := 90 * time.Second
+	previous := structs.GatewayConfiguration{ServiceName: "users", Retries: intPointer(3), Timeout: &previousTimeout}
+	current := structs.GatewayConfiguration{ServiceName: "users", Retries: intPointer(5), Timeout: &currentTimeout}
+	LogConfigChanges(&logger, "users", DiffGatewayConfigValues(previous, current))
+
+	entries := logEntries(t, &output)
+	if len(entries) != 1 {
+		t.Fatalf("expected a single log entry, got %d: %s", len(entries), output.String())
+	}
+	entry := entries[0]
+	if entry["level"] != "info" || entry["service"] != "users" {
+		t.Errorf("expected an info entry for the service users, got %v", entry)
+	}
+	if changedFields := entry["changedFields"]; !reflect.DeepEqual(changedFields, []interface{}{"Retries", "Timeout"}) {
+		t.Errorf("expected the changed fields [Retries Timeout], got %v", changedFields)
+	}
+	expectedDiff := []interface{}{
+		map[string]interface{}{"field": "Retries", "old": 3.0, "new": 5.0},
+		map[string]interface{}{"field": "Timeout", "old": "30s", "new": "1m30s"},
+	}
+	if !reflect.DeepEqual(entry["diff"], expectedDiff) {
+		t.Errorf("expected the diff %v, got %v", expectedDiff, entry["diff"])
+	}
+
+	output.Reset()
+	LogConfigChanges(&logger, "users", DiffGatewayConfigValues(previous, previous))
+	if output.Len() != 0 {
+		t.Errorf("unexpected output for equal configurations: %s", output.String())
+	}
+}
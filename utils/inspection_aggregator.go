@@ -0,0 +1,88 @@
+package utils
+
+import (
+	"sort"
+	"sync"
+	"time"
+
+	"github.com/rs/zerolog"
+)
+
+// InspectionStats summarizes the container inspections of a tick
+type InspectionStats struct {
+	Count    int
+	Failures int
+	Median   time.Duration
+	P95      time.Duration
+}
+
+// InspectionAggregator collects the duration and outcome of the container
+// inspections executed during a tick. It is safe for concurrent use
+type InspectionAggregator struct {
+	durations []time.Duration
+	failures  int
+	mutex     sync.Mutex
+}
+
+// Record adds the result of an inspection
+func (a *InspectionAggregator) Record(duration time.Duration, err error) {
+	a.mutex.Lock()
+	defer a.mutex.Unlock()
+	a.durations = append(a.durations, duration)
+	if err != nil {
+		a.failures++
+	}
+}
+
+// Stats computes the statistics of the recorded inspections
+func (a *InspectionAggregator) Stats() InspectionStats {
+	a.mutex.Lock()
+	durations := append([]time.Duration(nil), a.durations...)
+	failures := a.failures
+	a.mutex.Unlock()
+
+	sort.Slice(durations, func(i, j int) bool { return durations[i] < durations[j] })
+	return InspectionStats{
+		Count:    len(durations),
+		Failures: failures,
+		Median:   percentile(durations, 50),
+		P95:      percentile(durations, 95),
+	}
+}
+
+// Reset removes all recorded inspections, e.g. at the start of a tick
+func (a *InspectionAggregator) Reset() {
+	a.mutex.Lock()
+	defer a.mutex.Unlock()
+	a.durations = nil
+	a.failures = 0
+}
+
+// Report computes the statistics and logs a warning if the 95th percentile
+// exceeds the threshold. The statistics are returned for further use
+func (a *InspectionAggregator) Report(logger *zerolog.Logger, threshold time.Duration) InspectionStats {
+	stats := a.Stats()
+	if stats.Count > 0 && stats.P95 > threshold {
+		logger.Warn().
+			Int("inspections", stats.Count).
+			Int("failures", stats.Failures).
+			Dur("median", stats.Median).
+			Dur("p95", stats.P95).
+			Dur("threshold", threshold).
+			Msg("container inspections are slow, the docker api may be rate limited")
+	}
+	return stats
+}
+
+// percentile returns the value at the percentile of the sorted durations
+// using the nearest-rank method
+func percentile(sortedDurations []time.Duration, percent int) time.Duration {
+	if len(sortedDurations) == 0 {
+		return 0
+	}
+	rank := (percent*len(sortedDurations) + 99) / 100
+	if rank < 1 {
+		rank = 1
+	}
+	return sortedDurations[rank-1]
+}
@@ -0,0 +1,54 @@
+package utils
+
+import (
+	"errors"
+	"testing"
+	"time"
+)
+
+func TestInspectionAggregatorStats(t *testing.T) {
+	tests := []struct {
+		samples        int
+		expectedMedian time.Duration
+		expectedP95    time.Duration
+	}{
+		{1, 1 * time.Millisecond, 1 * time.Millisecond},
+		{2, 1 * time.Millisecond, 2 * time.Millisecond},
+		{20, 10 * time.Millisecond, 19 * time.Millisecond},
+		{100, 50 * time.Millisecond, 95 * time.Millisecond},
+	}
+	for _, test := range tests {
+		var aggregator InspectionAggregator
+		// record the durations 1ms to n ms in reverse order to check the
+		// sorting, the nearest rank then equals the duration in ms
+		for sample := test.samples; sample > 0; sample-- {
+			aggregator.Record(time.Duration(sample)*time.Millisecond, nil)
+		}
+		stats := aggregator.Stats()
+		if stats.Count != test.samples || stats.Failures != 0 {
+			t.Errorf("%d samples: unexpected count %d and failures %d", test.samples, stats.Count, stats.Failures)
+		}
+		if stats.Median != test.expectedMedian || stats.P95 != test.expectedP95 {
+			t.Errorf("%d samples: expected median %s and p95 %s, got %s and %s",
+				test.samples, test.expectedMedian, test.expectedP95, stats.Median, stats.P95)
+		}
+	}
+}
+
+func TestInspectionAggregatorFailuresAndReset(t *testing.T) {
+	var aggregator InspectionAggregator
+	if stats := aggregator.Stats(); stats != (InspectionStats{}) {
+		t.Errorf("expected empty statistics, got %+v", stats)
+	}
+
+	aggregator.Record(time.Second, nil)
+	aggregator.Record(3*time.Second, errors.New("timeout"))
+	if stats := aggregator.Stats(); stats.Count != 2 || stats.Failures != 1 {
+		t.Errorf("expected 2 inspections with 1 failure, got %+v", stats)
+	}
+
+	aggregator.Reset()
+	if stats := aggregator.Stats(); stats != (InspectionStats{}) {
+		t.Errorf("expected empty statistics after the reset, got %+v", stats)
+	}
+}
@@ -0,0 +1,35 @@
+package utils
+
+import (
+	"context"
+	"fmt"
+	"net/http"
+	"time"
+)
+
+// IntrospectionProbeTimeout is the time the introspection endpoint has to
+// answer a probe
+const IntrospectionProbeTimeout = 5 * time.Second
+
+// ProbeIntrospectionURL checks that the token introspection endpoint is
+// reachable before the global auth plugin is created or updated. Any
+// response below 500 counts as reachable since the probe is sent without a
+// token
+func ProbeIntrospectionURL(ctx context.Context, introspectionURL string) error {
+	ctx, cancel := context.WithTimeout(ctx, IntrospectionProbeTimeout)
+	defer cancel()
+
+	request, err := http.NewRequestWithContext(ctx, http.MethodPost, introspectionURL, nil)
+	if err != nil {
+		return fmt.Errorf("invalid introspection url '%s': %w", introspectionURL, err)
+	}
+	response, err := http.DefaultClient.Do(request)
+	if err != nil {
+		return fmt.Errorf("POST %s failed: %w", introspectionURL, err)
+	}
+	defer response.Body.Close()
+	if response.StatusCode >= http.StatusInternalServerError {
+		return fmt.Errorf("POST %s returned %s", introspectionURL, response.Status)
+	}
+	return nil
+}
@@ -0,0 +1,72 @@
+package utils
+
+import (
+	"context"
+	"net"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+	"time"
+)
+
+func TestProbeIntrospectionURL(t *testing.T) {
+	tests := []struct {
+		name      string
+		status    int
+		wantError bool
+	}{
+		{"ok", http.StatusOK, false},
+		{"unauthorized", http.StatusUnauthorized, false},
+		{"bad request", http.StatusBadRequest, false},
+		{"internal server error", http.StatusInternalServerError, true},
+		{"service unavailable", http.StatusServiceUnavailable, true},
+	}
+	for _, test := range tests {
+		t.Run(test.name, func(t *testing.T) {
+			server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+				if r.Method != http.MethodPost {
+					t.Errorf("unexpected method %s", r.Method)
+				}
+				w.WriteHeader(test.status)
+			}))
+			defer server.Close()
+
+			err := ProbeIntrospectionURL(context.Background(), server.URL+"/introspect")
+			if (err != nil) != test.wantError {
+				t.Errorf("expected error %v, got %v", test.wantError, err)
+			}
+		})
+	}
+}
+
+func TestProbeIntrospectionURLUnreachable(t *testing.T) {
+	listener, err := net.Listen("tcp", "127.0.0.1:0")
+	if err != nil {
+		t.Fatal(err)
+	}
+	address := listener.Addr().String()
+	listener.Close()
+
+	if err := ProbeIntrospectionURL(context.Background(), "http://"+address+"/introspect"); err == nil {
+		t.Errorf("expected an error for a refused connection")
+	}
+}
+
+func TestProbeIntrospectionURLTimeout(t *testing.T) {
+	release := make(chan struct{})
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		<-release
+	}))
+	defer server.Close()
+	defer close(release)
+
+	ctx, cancel := context.WithTimeout(context.Background(), 50*time.Millisecond)
+	defer cancel()
+	start := time.Now()
+	if err := ProbeIntrospectionURL(ctx, server.URL); err == nil {
+		t.Errorf("expected an error for an endpoint which does not answer")
+	}
+	if elapsed := time.Since(start); elapsed > IntrospectionProbeTimeout {
+		t.Errorf("the probe did not stop at the deadline, it took %s", elapsed)
+	}
+}
@@ -0,0 +1,57 @@
+package utils
+
+import (
+	"context"
+	"time"
+
+	"github.com/rs/zerolog"
+	"github.com/rs/zerolog/log"
+)
+
+// KongAPILogger writes debug log entries before and after calls to the Kong
+// admin api. The logger is taken from the context of the call and falls
+// back to the global logger if the context does not contain one
+type KongAPILogger struct {
+	// ResourceType is the type of the Kong objects the calls operate on,
+	// e.g. "service" or "target"
+	ResourceType string
+}
+
+// NewKongAPILogger creates a logger for calls operating on the resource type
+func NewKongAPILogger(resourceType string) KongAPILogger {
+	return KongAPILogger{ResourceType: resourceType}
+}
+
+// Call logs and executes the call. The error returned by the call is passed
+// through unchanged
+func (l KongAPILogger) Call(ctx context.Context, method string, resourceName string, call func(ctx context.Context) error) error {
+	logger := loggerFromContext(ctx).With().
+		Str("kong_method", method).
+		Str("resource_type", l.ResourceType).
+		Str("resource_name", resourceName).
+		Logger()
+
+	logger.Debug().Msg("calling kong admin api")
+	start := time.Now()
+	err := call(ctx)
+	duration := time.Since(start)
+	if err != nil {
+		logger.Debug().Dur("duration", duration).Str("status", "error").Err(err).Msg("kong admin api call failed")
+		return err
+	}
+	logger.Debug().Dur("duration", duration).Str("status", "success").Msg("kong admin api call finished")
+	return nil
+}
+
+// loggerFromContext returns the logger stored in the context or the global
+// logger if there is none. zerolog.Ctx returns the same fallback logger for
+// every context without a logger, so the stored logger is detected by
+// comparing it with the logger of an empty context. A stored logger is used
+// even if its level is disabled
+func loggerFromContext(ctx context.Context) *zerolog.Logger {
+	logger := zerolog.Ctx(ctx)
+	if logger == zerolog.Ctx(context.Background()) {
+		return &log.Logger
+	}
+	return logger
+}
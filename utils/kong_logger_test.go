@@ -0,0 +1,118 @@
+package utils
+
+import (
+	"bytes"
+	"context"
+	"encoding/json"
+	"errors"
+	"strings"
+	"testing"
+
+	"github.com/rs/zerolog"
+	"github.com/rs/zerolog/log"
+)
+
+// setGlobalLogger replaces the global logger with one writing to the returned
+// buffer for the duration of the test
+func setGlobalLogger(t *testing.T) *bytes.Buffer {
+	t.Helper()
+	var output bytes.Buffer
+	previousLogger := log.Logger
+	log.Logger = zerolog.New(&output)
+	t.Cleanup(func() { log.Logger = previousLogger })
+	return &output
+}
+
+// logEntries decodes the json log entries written to the buffer
+func logEntries(t *testing.T, output *bytes.Buffer) []map[string]interface{} {
+	t.Helper()
+	var entries []map[string]interface{}
+	for _, line := range strings.Split(strings.TrimSpace(output.String()), "\n") {
+		if line == "" {
+			continue
+		}
+		var entry map[string]interface{}
+		if err := json.Unmarshal([]byte(line), &entry); err != nil {
+			t.Fatalf("invalid log entry %q: %v", line, err)
+		}
+		entries = append(entries, entry)
+	}
+	return entries
+}
+
+func TestKongAPILoggerCall(t *testing.T) {
+	callErr := errors.New("connection refused")
+	tests := []struct {
+		name   string
+		err    error
+		status string
+	}{
+		{"success", nil, "success"},
+		{"error", callErr, "error"},
+	}
+	for _, test := range tests {
+		t.Run(test.name, func(t *testing.T) {
+			globalOutput := setGlobalLogger(t)
+			var output bytes.Buffer
+			ctx := zerolog.New(&output).With().Str("container", "users").Logger().WithContext(context.Background())
+
+			err := NewKongAPILogger("service").Call(ctx, "POST", "users", func(context.Context) error {
+				return test.err
+			})
+			if err != test.err {
+				t.Errorf("expected the error %v to be passed through, got %v", test.err, err)
+			}
+
+			entries := logEntries(t, &output)
+			if len(entries) != 2 {
+				t.Fatalf("expected two log entries, got %d: %s", len(entries), output.String())
+			}
+			for _, entry := range entries {
+				if entry["level"] != "debug" || entry["kong_method"] != "POST" || entry["resource_type"] != "service" ||
+					entry["resource_name"] != "users" || entry["container"] != "users" {
+					t.Errorf("missing call fields in %v", entry)
+				}
+			}
+			if _, hasDuration := entries[0]["duration"]; hasDuration {
+				t.Errorf("unexpected duration before the call: %v", entries[0])
+			}
+			if _, hasDuration := entries[1]["duration"]; !hasDuration || entries[1]["status"] != test.status {
+				t.Errorf("expected duration and status %s after the call: %v", test.status, entries[1])
+			}
+			if test.err != nil && entries[1]["error"] != test.err.Error() {
+				t.Errorf("expected the error in %v", entries[1])
+			}
+			if globalOutput.Len() != 0 {
+				t.Errorf("unexpected output of the global logger: %s", globalOutput.String())
+			}
+		})
+	}
+}
+
+func TestKongAPILoggerFallback(t *testing.T) {
+	globalOutput := setGlobalLogger(t)
+	if err := NewKongAPILogger("target").Call(context.Background(), "DELETE", "users", func(context.Context) error {
+		return nil
+	}); err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	if entries := logEntries(t, globalOutput); len(entries) != 2 || entries[0]["resource_type"] != "target" {
+		t.Errorf("expected two entries of the global logger, got %s", globalOutput.String())
+	}
+}
+
+func TestKongAPILoggerDisabledContextLogger(t *testing.T) {
+	globalOutput := setGlobalLogger(t)
+	var output bytes.Buffer
+	ctx := zerolog.New(&output).WithContext(context.Background())
+	ctx = zerolog.New(&output).Level(zerolog.Disabled).WithContext(ctx)
+
+	if err := NewKongAPILogger("service").Call(ctx, "GET", "users", func(context.Context) error {
+		return nil
+	}); err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	if output.Len() != 0 || globalOutput.Len() != 0 {
+		t.Errorf("expected the disabled context logger to drop the entries, got %q and %q", output.String(), globalOutput.String())
+	}
+}
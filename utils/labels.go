@@ -0,0 +1,72 @@
+package utils
+
+import (
+	"bufio"
+	"fmt"
+	"io"
+	"os"
+	"strings"
+)
+
+// DefaultsLabelPrefix is the prefix of label defaults. A default for a label
+// is written as the label with "wisdom-oss.service." replaced by this
+// prefix, e.g. "wisdom-oss.defaults.service.rate-limit.minute=100". Only
+// service labels may have defaults, so wisdom-oss.isService always needs to
+// be set on the container itself
+const DefaultsLabelPrefix = "wisdom-oss.defaults.service."
+
+const serviceLabelPrefix = "wisdom-oss.service."
+
+// MergeLabels returns a new map containing the base labels overwritten by
+// the override labels. Neither of the supplied maps is modified
+func MergeLabels(base, override map[string]string) map[string]string {
+	merged := make(map[string]string, len(base)+len(override))
+	for key, value := range base {
+		merged[key] = value
+	}
+	for key, value := range override {
+		merged[key] = value
+	}
+	return merged
+}
+
+// ParseLabelDefaults reads label defaults formatted as one "key=value" pair
+// per line. Empty lines and lines starting with '#' are ignored. The returned
+// map is keyed by the label the default applies to, so it can be used as
+// base for MergeLabels
+func ParseLabelDefaults(r io.Reader) (map[string]string, error) {
+	defaults := make(map[string]string)
+	scanner := bufio.NewScanner(r)
+	lineNumber := 0
+	for scanner.Scan() {
+		lineNumber++
+		line := strings.TrimSpace(scanner.Text())
+		if line == "" || strings.HasPrefix(line, "#") {
+			continue
+		}
+		key, value, found := strings.Cut(line, "=")
+		if !found {
+			return nil, fmt.Errorf("line %d: expected a key=value pair", lineNumber)
+		}
+		key = strings.TrimSpace(key)
+		if !strings.HasPrefix(key, DefaultsLabelPrefix) || key == DefaultsLabelPrefix {
+			return nil, fmt.Errorf("line %d: label '%s' does not start with '%s'", lineNumber, key, DefaultsLabelPrefix)
+		}
+		defaults[serviceLabelPrefix+strings.TrimPrefix(key, DefaultsLabelPrefix)] = strings.TrimSpace(value)
+	}
+	if err := scanner.Err(); err != nil {
+		return nil, err
+	}
+	return defaults, nil
+}
+
+// LoadLabelDefaults reads the label defaults from the file at the path. See
+// ParseLabelDefaults for the expected format
+func LoadLabelDefaults(path string) (map[string]string, error) {
+	file, err := os.Open(path)
+	if err != nil {
+		return nil, err
+	}
+	defer file.Close()
+	return ParseLabelDefaults(file)
+}
@@ -0,0 +1,98 @@
+package utils
+
+import (
+	"os"
+	"path/filepath"
+	"reflect"
+	"strings"
+	"testing"
+)
+
+func TestMergeLabels(t *testing.T) {
+	base := map[string]string{"wisdom-oss.service.retries": "3", "wisdom-oss.service.weight": "50"}
+	override := map[string]string{"wisdom-oss.service.retries": "0", "wisdom-oss.service.name": "users"}
+
+	merged := MergeLabels(base, override)
+	expected := map[string]string{
+		"wisdom-oss.service.retries": "0",
+		"wisdom-oss.service.weight":  "50",
+		"wisdom-oss.service.name":    "users",
+	}
+	if !reflect.DeepEqual(merged, expected) {
+		t.Errorf("expected %v, got %v", expected, merged)
+	}
+	if !reflect.DeepEqual(base, map[string]string{"wisdom-oss.service.retries": "3", "wisdom-oss.service.weight": "50"}) {
+		t.Errorf("the base labels have been modified: %v", base)
+	}
+	if !reflect.DeepEqual(override, map[string]string{"wisdom-oss.service.retries": "0", "wisdom-oss.service.name": "users"}) {
+		t.Errorf("the override labels have been modified: %v", override)
+	}
+	if merged := MergeLabels(nil, nil); merged == nil || len(merged) != 0 {
+		t.Errorf("expected an empty map, got %v", merged)
+	}
+}
+
+func TestParseLabelDefaults(t *testing.T) {
+	tests := []struct {
+		name      string
+		input     string
+		expected  map[string]string
+		wantError bool
+	}{
+		{
+			name:     "pairs",
+			input:    "wisdom-oss.defaults.service.rate-limit.minute=100\n wisdom-oss.defaults.service.retries = 3 \n",
+			expected: map[string]string{"wisdom-oss.service.rate-limit.minute": "100", "wisdom-oss.service.retries": "3"},
+		},
+		{
+			name:     "comments and blank lines",
+			input:    "# shared defaults\n\n   \nwisdom-oss.defaults.service.timeout=30s\n",
+			expected: map[string]string{"wisdom-oss.service.timeout": "30s"},
+		},
+		{
+			name:     "value containing a separator",
+			input:    "wisdom-oss.defaults.service.plugin.acl=allow=users\n",
+			expected: map[string]string{"wisdom-oss.service.plugin.acl": "allow=users"},
+		},
+		{name: "empty", input: "", expected: map[string]string{}},
+		{name: "missing separator", input: "wisdom-oss.defaults.service.retries\n", wantError: true},
+		{name: "bad prefix", input: "wisdom-oss.service.retries=3\n", wantError: true},
+		{name: "prefix only", input: "wisdom-oss.defaults.service.=3\n", wantError: true},
+		{name: "is service", input: "wisdom-oss.defaults.isService=true\n", wantError: true},
+	}
+	for _, test := range tests {
+		t.Run(test.name, func(t *testing.T) {
+			defaults, err := ParseLabelDefaults(strings.NewReader(test.input))
+			if test.wantError {
+				if err == nil {
+					t.Errorf("expected an error, got %v", defaults)
+				}
+				return
+			}
+			if err != nil {
+				t.Fatalf("unexpected error: %v", err)
+			}
+			if !reflect.DeepEqual(defaults, test.expected) {
+				t.Errorf("expected %v, got %v", test.expected, defaults)
+			}
+		})
+	}
+}
+
+func TestLoadLabelDefaults(t *testing.T) {
+	path := filepath.Join(t.TempDir(), "defaults")
+	if err := os.WriteFile(path, []byte("wisdom-oss.defaults.service.retries=3\n"), 0o600); err != nil {
+		t.Fatal(err)
+	}
+	defaults, err := LoadLabelDefaults(path)
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	if !reflect.DeepEqual(defaults, map[string]string{"wisdom-oss.service.retries": "3"}) {
+		t.Errorf("unexpected defaults %v", defaults)
+	}
+
+	if _, err := LoadLabelDefaults(filepath.Join(t.TempDir(), "missing")); err == nil {
+		t.Errorf("expected an error for a missing file")
+	}
+}
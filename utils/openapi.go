@@ -0,0 +1,77 @@
+package utils
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"io"
+	"net/http"
+	"os"
+	"path/filepath"
+	"time"
+)
+
+// OpenAPIMaxSize is the largest openapi specification in bytes which is
+// fetched from a service
+const OpenAPIMaxSize = 5 << 20
+
+// OpenAPIFetchTimeout limits the time for fetching the openapi specification
+// of a service
+var OpenAPIFetchTimeout = 10 * time.Second
+
+// FetchOpenAPISpec downloads the openapi specification from the target of a
+// service. The specification needs to be a json document not larger than
+// OpenAPIMaxSize bytes
+func FetchOpenAPISpec(ctx context.Context, targetAddress string, specPath string) ([]byte, error) {
+	ctx, cancel := context.WithTimeout(ctx, OpenAPIFetchTimeout)
+	defer cancel()
+
+	specURL := fmt.Sprintf("http://%s%s", targetAddress, specPath)
+	request, err := http.NewRequestWithContext(ctx, http.MethodGet, specURL, nil)
+	if err != nil {
+		return nil, err
+	}
+	request.Header.Set("Accept", "application/json")
+	response, err := http.DefaultClient.Do(request)
+	if err != nil {
+		return nil, fmt.Errorf("GET %s failed: %w", specURL, err)
+	}
+	defer response.Body.Close()
+	if response.StatusCode != http.StatusOK {
+		return nil, fmt.Errorf("GET %s returned %s", specURL, response.Status)
+	}
+
+	spec, err := io.ReadAll(io.LimitReader(response.Body, OpenAPIMaxSize+1))
+	if err != nil {
+		return nil, fmt.Errorf("unable to read the openapi specification from %s: %w", specURL, err)
+	}
+	if len(spec) > OpenAPIMaxSize {
+		return nil, fmt.Errorf("the openapi specification at %s is larger than %d bytes", specURL, OpenAPIMaxSize)
+	}
+	if !json.Valid(spec) {
+		return nil, fmt.Errorf("the openapi specification at %s is not a json document", specURL)
+	}
+	return spec, nil
+}
+
+// StoreOpenAPISpec writes the specification of the service into the
+// directory as "<service>.json". The file is replaced atomically so readers
+// never see a partially written specification
+func StoreOpenAPISpec(directory string, serviceName string, spec []byte) error {
+	if filepath.Base(serviceName) != serviceName || serviceName == "." || serviceName == ".." {
+		return fmt.Errorf("invalid service name '%s' for an openapi specification file", serviceName)
+	}
+	file, err := os.CreateTemp(directory, serviceName+".*.tmp")
+	if err != nil {
+		return err
+	}
+	defer os.Remove(file.Name())
+	if _, err := file.Write(spec); err != nil {
+		file.Close()
+		return err
+	}
+	if err := file.Close(); err != nil {
+		return err
+	}
+	return os.Rename(file.Name(), filepath.Join(directory, serviceName+".json"))
+}
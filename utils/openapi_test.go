@@ -0,0 +1,117 @@
+package utils
+
+import (
+	"bytes"
+	"context"
+	"net/http"
+	"net/http/httptest"
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+	"time"
+)
+
+func TestFetchOpenAPISpec(t *testing.T) {
+	tests := []struct {
+		name      string
+		status    int
+		body      []byte
+		wantError string
+	}{
+		{"json document", http.StatusOK, []byte(`{"openapi":"3.0.0"}`), ""},
+		{"largest document", http.StatusOK, append(append([]byte(`"`), bytes.Repeat([]byte("a"), OpenAPIMaxSize-2)...), '"'), ""},
+		{"too large", http.StatusOK, append(append([]byte(`"`), bytes.Repeat([]byte("a"), OpenAPIMaxSize-1)...), '"'), "larger than"},
+		{"not found", http.StatusNotFound, []byte(`{}`), "404"},
+		{"not json", http.StatusOK, []byte("openapi: 3.0.0"), "not a json document"},
+	}
+	for _, test := range tests {
+		t.Run(test.name, func(t *testing.T) {
+			server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+				if r.URL.Path != "/docs/openapi.json" {
+					t.Errorf("unexpected request to %s", r.URL.Path)
+				}
+				w.WriteHeader(test.status)
+				w.Write(test.body)
+			}))
+			defer server.Close()
+
+			spec, err := FetchOpenAPISpec(context.Background(), strings.TrimPrefix(server.URL, "http://"), "/docs/openapi.json")
+			if test.wantError != "" {
+				if err == nil || !strings.Contains(err.Error(), test.wantError) {
+					t.Fatalf("expected an error mentioning '%s', got %v", test.wantError, err)
+				}
+				return
+			}
+			if err != nil {
+				t.Fatalf("unexpected error: %v", err)
+			}
+			if !bytes.Equal(spec, test.body) {
+				t.Errorf("the specification has been changed")
+			}
+		})
+	}
+}
+
+func TestFetchOpenAPISpecTimeout(t *testing.T) {
+	previousTimeout := OpenAPIFetchTimeout
+	OpenAPIFetchTimeout = 50 * time.Millisecond
+	t.Cleanup(func() { OpenAPIFetchTimeout = previousTimeout })
+
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		select {
+		case <-r.Context().Done():
+		case <-time.After(5 * time.Second):
+		}
+	}))
+	defer server.Close()
+
+	start := time.Now()
+	if _, err := FetchOpenAPISpec(context.Background(), strings.TrimPrefix(server.URL, "http://"), "/openapi.json"); err == nil {
+		t.Fatalf("expected an error for a target which does not answer")
+	}
+	if elapsed := time.Since(start); elapsed > time.Second {
+		t.Errorf("the fetch has not been aborted after the timeout, it took %s", elapsed)
+	}
+}
+
+func TestStoreOpenAPISpec(t *testing.T) {
+	directory := t.TempDir()
+	specFile := filepath.Join(directory, "users.json")
+	if err := os.WriteFile(specFile, []byte(`{"openapi":"3.0.0"}`), 0o644); err != nil {
+		t.Fatal(err)
+	}
+
+	if err := StoreOpenAPISpec(directory, "users", []byte(`{"openapi":"3.1.0"}`)); err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	spec, err := os.ReadFile(specFile)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if string(spec) != `{"openapi":"3.1.0"}` {
+		t.Errorf("the specification has not been replaced, got %s", spec)
+	}
+	entries, err := os.ReadDir(directory)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if len(entries) != 1 {
+		t.Errorf("expected only the specification file to remain, got %v", entries)
+	}
+}
+
+func TestStoreOpenAPISpecInvalidName(t *testing.T) {
+	directory := t.TempDir()
+	for _, serviceName := range []string{"../x", "a/b", ".", ".."} {
+		if err := StoreOpenAPISpec(directory, serviceName, []byte(`{}`)); err == nil {
+			t.Errorf("expected an error for the service name '%s'", serviceName)
+		}
+	}
+	if entries, _ := os.ReadDir(directory); len(entries) != 0 {
+		t.Errorf("unexpected files %v", entries)
+	}
+	if _, err := os.Stat(filepath.Join(filepath.Dir(directory), "x.json")); err == nil {
+		t.Errorf("the specification has been written outside of the directory")
+	}
+}
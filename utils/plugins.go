@@ -0,0 +1,43 @@
+package utils
+
+import (
+	"fmt"
+	"strings"
+
+	"gateway-service-watcher/structs"
+)
+
+// PluginLabelPrefix is the prefix of labels configuring a Kong plugin for a
+// service. The remainder of the label key is the name of the plugin
+const PluginLabelPrefix = "wisdom-oss.service.plugin."
+
+// ParsePluginConfigLabel returns the name of the plugin and its
+// configuration for a plugin label
+func ParsePluginConfigLabel(label, value string) (string, structs.PluginConfig, error) {
+	pluginName := strings.TrimPrefix(label, PluginLabelPrefix)
+	if pluginName == label || pluginName == "" {
+		return "", nil, fmt.Errorf("label '%s' does not configure a plugin", label)
+	}
+	var config structs.PluginConfig
+	if err := config.UnmarshalLabel(value); err != nil {
+		return "", nil, fmt.Errorf("plugin '%s': %w", pluginName, err)
+	}
+	return pluginName, config, nil
+}
+
+// RequestSizeLimitingPlugin is the name of the Kong plugin limiting the size
+// of request payloads
+const RequestSizeLimitingPlugin = "request-size-limiting"
+
+// RequestSizeLimitingConfig returns the configuration of the
+// request-size-limiting plugin for the service. The plugin is not needed if
+// the service does not limit the request size, which is reported by false
+func RequestSizeLimitingConfig(config structs.GatewayConfiguration) (structs.PluginConfig, bool) {
+	if config.MaxRequestSize == nil {
+		return nil, false
+	}
+	return structs.PluginConfig{
+		"allowed_payload_size": *config.MaxRequestSize,
+		"size_unit":            "megabytes",
+	}, true
+}
@@ -0,0 +1,43 @@
+package utils
+
+import (
+	"reflect"
+	"testing"
+
+	"gateway-service-watcher/structs"
+)
+
+func TestParsePluginConfigLabel(t *testing.T) {
+	pluginName, config, err := ParsePluginConfigLabel(PluginLabelPrefix+"rate-limiting", "minute=100;policy=local")
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	if pluginName != "rate-limiting" {
+		t.Errorf("expected plugin rate-limiting, got %s", pluginName)
+	}
+	if expected := (structs.PluginConfig{"minute": 100, "policy": "local"}); !reflect.DeepEqual(config, expected) {
+		t.Errorf("expected %v, got %v", expected, config)
+	}
+
+	for _, label := range []string{PluginLabelPrefix, structs.ServiceNameLabel} {
+		if _, _, err := ParsePluginConfigLabel(label, "minute=100"); err == nil {
+			t.Errorf("expected an error for label %s", label)
+		}
+	}
+}
+
+func TestRequestSizeLimitingConfig(t *testing.T) {
+	if config, needed := RequestSizeLimitingConfig(structs.GatewayConfiguration{}); needed || config != nil {
+		t.Errorf("expected no plugin without a maximal request size, got %v", config)
+	}
+
+	maxRequestSize := structs.MaxRequestSizeLimit
+	config, needed := RequestSizeLimitingConfig(structs.GatewayConfiguration{MaxRequestSize: &maxRequestSize})
+	if !needed {
+		t.Fatalf("expected the plugin to be needed")
+	}
+	expected := structs.PluginConfig{"allowed_payload_size": 2048, "size_unit": "megabytes"}
+	if !reflect.DeepEqual(config, expected) {
+		t.Errorf("expected %v, got %v", expected, config)
+	}
+}
@@ -0,0 +1,96 @@
+package utils
+
+import (
+	"bytes"
+	"context"
+	"encoding/json"
+	"fmt"
+	"net/http"
+	"sync"
+	"time"
+)
+
+// ScalingEvent is sent to the scaling webhook if the number of targets of an
+// upstream changed significantly between two ticks
+type ScalingEvent struct {
+	UpstreamName string    `json:"upstreamName"`
+	OldCount     int       `json:"oldCount"`
+	NewCount     int       `json:"newCount"`
+	Timestamp    time.Time `json:"timestamp"`
+}
+
+// ScalingTracker remembers the target count of each upstream between ticks
+type ScalingTracker struct {
+	thresholdPercent float64
+	counts           map[string]int
+	mutex            sync.Mutex
+}
+
+// NewScalingTracker creates a tracker which reports changes of more than
+// thresholdPercent percent
+func NewScalingTracker(thresholdPercent float64) *ScalingTracker {
+	return &ScalingTracker{
+		thresholdPercent: thresholdPercent,
+		counts:           make(map[string]int),
+	}
+}
+
+// Observe records the target count of the upstream. A scaling event is
+// returned if the count changed by more than the threshold since the last
+// observation. The first observation of an upstream never returns an event
+func (t *ScalingTracker) Observe(upstreamName string, count int) *ScalingEvent {
+	t.mutex.Lock()
+	defer t.mutex.Unlock()
+
+	oldCount, known := t.counts[upstreamName]
+	t.counts[upstreamName] = count
+	if !known || oldCount == count {
+		return nil
+	}
+	if oldCount != 0 {
+		change := float64(count-oldCount) / float64(oldCount) * 100
+		if change < 0 {
+			change = -change
+		}
+		if change <= t.thresholdPercent {
+			return nil
+		}
+	}
+	return &ScalingEvent{
+		UpstreamName: upstreamName,
+		OldCount:     oldCount,
+		NewCount:     count,
+		Timestamp:    time.Now(),
+	}
+}
+
+// Forget removes the upstream from the tracker, e.g. after it was deleted
+func (t *ScalingTracker) Forget(upstreamName string) {
+	t.mutex.Lock()
+	defer t.mutex.Unlock()
+	delete(t.counts, upstreamName)
+}
+
+// SendScalingEvent posts the event as json to the webhook
+func SendScalingEvent(ctx context.Context, webhookURL string, event ScalingEvent) error {
+	payload, err := json.Marshal(event)
+	if err != nil {
+		return err
+	}
+	ctx, cancel := context.WithTimeout(ctx, 5*time.Second)
+	defer cancel()
+	request, err := http.NewRequestWithContext(ctx, http.MethodPost, webhookURL, bytes.NewReader(payload))
+	if err != nil {
+		return err
+	}
+	request.Header.Set("Content-Type", "application/json")
+	response, err := http.DefaultClient.Do(request)
+	if err != nil {
+		return fmt.Errorf("unable to send scaling event: %w", err)
+	}
+	defer response.Body.Close()
+	if response.StatusCode >= http.StatusBadRequest {
+		return fmt.Errorf("scaling webhook returned %s", response.Status)
+	}
+	return nil
+}
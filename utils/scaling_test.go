@@ -0,0 +1,109 @@
+package utils
+
+import (
+	"context"
+	"encoding/json"
+	"io"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+	"time"
+)
+
+func TestScalingTrackerObserve(t *testing.T) {
+	tests := []struct {
+		name      string
+		oldCount  int
+		newCount  int
+		wantEvent bool
+	}{
+		{"unchanged", 4, 4, false},
+		{"zero to some", 0, 3, true},
+		{"some to zero", 3, 0, true},
+		{"below threshold", 4, 5, false},
+		{"exactly at threshold up", 4, 6, false},
+		{"exactly at threshold down", 4, 2, false},
+		{"above threshold up", 4, 7, true},
+		{"above threshold down", 4, 1, true},
+	}
+	for _, test := range tests {
+		t.Run(test.name, func(t *testing.T) {
+			tracker := NewScalingTracker(50)
+			if event := tracker.Observe("users", test.oldCount); event != nil {
+				t.Fatalf("unexpected event for the first observation: %+v", event)
+			}
+			event := tracker.Observe("users", test.newCount)
+			if !test.wantEvent {
+				if event != nil {
+					t.Errorf("unexpected event: %+v", event)
+				}
+				return
+			}
+			if event == nil {
+				t.Fatalf("expected an event")
+			}
+			if event.UpstreamName != "users" || event.OldCount != test.oldCount || event.NewCount != test.newCount {
+				t.Errorf("unexpected event: %+v", event)
+			}
+		})
+	}
+}
+
+func TestScalingTrackerForget(t *testing.T) {
+	tracker := NewScalingTracker(50)
+	tracker.Observe("users", 1)
+	tracker.Forget("users")
+	if event := tracker.Observe("users", 10); event != nil {
+		t.Errorf("unexpected event after forgetting the upstream: %+v", event)
+	}
+}
+
+func TestSendScalingEvent(t *testing.T) {
+	var payload map[string]interface{}
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		if contentType := r.Header.Get("Content-Type"); contentType != "application/json" {
+			t.Errorf("unexpected content type %s", contentType)
+		}
+		body, _ := io.ReadAll(r.Body)
+		if err := json.Unmarshal(body, &payload); err != nil {
+			t.Errorf("invalid json payload: %v", err)
+		}
+	}))
+	defer server.Close()
+
+	event := ScalingEvent{
+		UpstreamName: "users",
+		OldCount:     2,
+		NewCount:     5,
+		Timestamp:    time.Date(2024, 5, 1, 12, 0, 0, 0, time.UTC),
+	}
+	if err := SendScalingEvent(context.Background(), server.URL, event); err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+
+	expected := map[string]interface{}{
+		"upstreamName": "users",
+		"oldCount":     float64(2),
+		"newCount":     float64(5),
+		"timestamp":    "2024-05-01T12:00:00Z",
+	}
+	if len(payload) != len(expected) {
+		t.Errorf("expected the fields %v, got %v", expected, payload)
+	}
+	for field, value := range expected {
+		if payload[field] != value {
+			t.Errorf("field %s: expected %v, got %v", field, value, payload[field])
+		}
+	}
+}
+
+func TestSendScalingEventErrorStatus(t *testing.T) {
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		w.WriteHeader(http.StatusServiceUnavailable)
+	}))
+	defer server.Close()
+
+	if err := SendScalingEvent(context.Background(), server.URL, ScalingEvent{UpstreamName: "users"}); err == nil {
+		t.Errorf("expected an error for a failing webhook")
+	}
+}
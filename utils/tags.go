@@ -0,0 +1,67 @@
+package utils
+
+import (
+	"fmt"
+	"strings"
+	"unicode/utf8"
+
+	"gateway-service-watcher/structs"
+)
+
+// Prefixes of the tags storing the metadata of a service on the Kong service
+const (
+	DescriptionTagPrefix = "desc:"
+	DocsURLTagPrefix     = "docs:"
+)
+
+// MaxTagLength is the maximal length of a tag value in bytes, including its
+// prefix
+const MaxTagLength = 128
+
+// MetadataTags returns the tags carrying the description and documentation
+// url of the service
+func MetadataTags(config structs.GatewayConfiguration) []string {
+	var tags []string
+	if config.Description != "" {
+		tags = append(tags, MetadataTag(DescriptionTagPrefix, config.Description))
+	}
+	if config.DocsURL != "" {
+		tags = append(tags, MetadataTag(DocsURLTagPrefix, config.DocsURL))
+	}
+	return tags
+}
+
+// MetadataTag builds a tag from the prefix and value. Characters Kong does
+// not accept in tags and bytes which are not valid UTF-8 are percent-encoded
+// and the tag is truncated to MaxTagLength without splitting an encoded
+// character
+func MetadataTag(prefix, value string) string {
+	var tag strings.Builder
+	tag.WriteString(prefix)
+	for len(value) > 0 {
+		character, size := utf8.DecodeRuneInString(value)
+		encoded := encodeTagCharacter(character, value[:size])
+		if tag.Len()+len(encoded) > MaxTagLength {
+			break
+		}
+		tag.WriteString(encoded)
+		value = value[size:]
+	}
+	return tag.String()
+}
+
+// encodeTagCharacter percent-encodes characters which are not allowed in
+// Kong tags. These are commas, slashes, whitespace and control characters.
+// The percent sign is encoded too, so that the value can be decoded again.
+// The raw bytes are used to encode invalid UTF-8, which is decoded as
+// utf8.RuneError
+func encodeTagCharacter(character rune, raw string) string {
+	switch {
+	case character == ',' || character == '/' || character == '%' || character <= ' ' || character == 0x7f:
+		return fmt.Sprintf("%%%02X", character)
+	case character == utf8.RuneError && len(raw) == 1:
+		return fmt.Sprintf("%%%02X", raw[0])
+	default:
+		return raw
+	}
+}
@@ -0,0 +1,56 @@
+package utils
+
+import (
+	"strings"
+	"testing"
+)
+
+func TestMetadataTag(t *testing.T) {
+	tests := []struct {
+		name     string
+		value    string
+		expected string
+	}{
+		{"plain", "User management", "desc:User%20management"},
+		{"reserved characters", "a,b/c%d", "desc:a%2Cb%2Fc%25d"},
+		{"control characters", "a\tb\nc\x7f", "desc:a%09b%0Ac%7F"},
+		{"non-ascii", "Benutzerverwaltung für Ämter", "desc:Benutzerverwaltung%20für%20Ämter"},
+		{"replacement character", "a�b", "desc:a�b"},
+		{"invalid utf-8", "a\xffb\xc3", "desc:a%FFb%C3"},
+	}
+	for _, test := range tests {
+		t.Run(test.name, func(t *testing.T) {
+			if tag := MetadataTag(DescriptionTagPrefix, test.value); tag != test.expected {
+				t.Errorf("expected %q, got %q", test.expected, tag)
+			}
+		})
+	}
+}
+
+func TestMetadataTagTruncation(t *testing.T) {
+	// the prefix and filler leave two bytes, so neither the three bytes of
+	// an escape sequence nor of the multibyte character fit
+	filler := strings.Repeat("a", MaxTagLength-len(DescriptionTagPrefix)-2)
+	tests := []struct {
+		name     string
+		value    string
+		expected string
+	}{
+		{"fits exactly", filler + "bb", DescriptionTagPrefix + filler + "bb"},
+		{"escape sequence", filler + ",b", DescriptionTagPrefix + filler},
+		{"invalid byte", filler + "\xff", DescriptionTagPrefix + filler},
+		{"multibyte character", filler + "€", DescriptionTagPrefix + filler},
+		{"escape sequence after a fitting character", filler + "b/", DescriptionTagPrefix + filler + "b"},
+	}
+	for _, test := range tests {
+		t.Run(test.name, func(t *testing.T) {
+			tag := MetadataTag(DescriptionTagPrefix, test.value)
+			if len(tag) > MaxTagLength {
+				t.Fatalf("tag has %d bytes, the maximum is %d", len(tag), MaxTagLength)
+			}
+			if tag != test.expected {
+				t.Errorf("expected %q, got %q", test.expected, tag)
+			}
+		})
+	}
+}
@@ -0,0 +1,85 @@
+// Package watcherrors contains the error categories used by the watchdog to
+// decide how a failure is handled
+package watcherrors
+
+import (
+	"errors"
+	"fmt"
+)
+
+// Categories of errors. Underlying errors are wrapped into one of these with
+// Wrap, so callers can check the category with errors.Is
+var (
+	ErrDockerUnavailable = errors.New("docker unavailable")
+	ErrKongUnavailable   = errors.New("kong unavailable")
+	ErrValidation        = errors.New("validation failed")
+	ErrConflict          = errors.New("conflict")
+	ErrNotFound          = errors.New("not found")
+)
+
+// categories maps the category errors to the names used as metric labels
+var categories = []struct {
+	err  error
+	name string
+}{
+	{ErrDockerUnavailable, "docker_unavailable"},
+	{ErrKongUnavailable, "kong_unavailable"},
+	{ErrValidation, "validation"},
+	{ErrConflict, "conflict"},
+	{ErrNotFound, "not_found"},
+}
+
+// UnknownCategory is returned by Category for errors without a category
+const UnknownCategory = "unknown"
+
+// categorizedError joins an error category with the underlying error
+type categorizedError struct {
+	category error
+	err      error
+}
+
+func (e *categorizedError) Error() string {
+	return fmt.Sprintf("%s: %s", e.category, e.err)
+}
+
+func (e *categorizedError) Unwrap() error {
+	return e.err
+}
+
+func (e *categorizedError) Is(target error) bool {
+	return target == e.category
+}
+
+// Wrap puts the error into the category. The underlying error stays
+// reachable through errors.Is and errors.As. A nil error is returned as nil
+func Wrap(category error, err error) error {
+	if err == nil {
+		return nil
+	}
+	return &categorizedError{category: category, err: err}
+}
+
+// Category returns the name of the category of the error for use as metric
+// label
+func Category(err error) string {
+	for _, category := range categories {
+		if errors.Is(err, category.err) {
+			return category.name
+		}
+	}
+	return UnknownCategory
+}
+
+// Retryable reports whether the failed operation should be retried. This is
+// the case for unavailable services and conflicts
+func Retryable(err error) bool {
+	return errors.Is(err, ErrDockerUnavailable) ||
+		errors.Is(err, ErrKongUnavailable) ||
+		errors.Is(err, ErrConflict)
+}
+
+// Quarantine reports whether the container causing the error should be
+// quarantined instead of being retried
+func Quarantine(err error) bool {
+	return errors.Is(err, ErrValidation)
+}
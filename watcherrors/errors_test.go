@@ -0,0 +1,73 @@
+package watcherrors
+
+import (
+	"errors"
+	"fmt"
+	"io/fs"
+	"testing"
+)
+
+func TestWrap(t *testing.T) {
+	underlying := &fs.PathError{Op: "dial", Path: "/var/run/docker.sock", Err: fs.ErrNotExist}
+	err := Wrap(ErrDockerUnavailable, fmt.Errorf("listing containers: %w", underlying))
+
+	if !errors.Is(err, ErrDockerUnavailable) {
+		t.Errorf("the category is not reachable through errors.Is")
+	}
+	if errors.Is(err, ErrKongUnavailable) {
+		t.Errorf("the error matches a different category")
+	}
+	if !errors.Is(err, fs.ErrNotExist) {
+		t.Errorf("the underlying error is not reachable through errors.Is")
+	}
+	var pathError *fs.PathError
+	if !errors.As(err, &pathError) || pathError != underlying {
+		t.Errorf("the underlying error is not reachable through errors.As")
+	}
+	if message := err.Error(); message != "docker unavailable: listing containers: dial /var/run/docker.sock: file does not exist" {
+		t.Errorf("unexpected message '%s'", message)
+	}
+	if Wrap(ErrConflict, nil) != nil {
+		t.Errorf("a nil error has not been kept nil")
+	}
+}
+
+func TestCategories(t *testing.T) {
+	tests := []struct {
+		category   error
+		name       string
+		retryable  bool
+		quarantine bool
+	}{
+		{ErrDockerUnavailable, "docker_unavailable", true, false},
+		{ErrKongUnavailable, "kong_unavailable", true, false},
+		{ErrValidation, "validation", false, true},
+		{ErrConflict, "conflict", true, false},
+		{ErrNotFound, "not_found", false, false},
+	}
+	if len(tests) != len(categories) {
+		t.Fatalf("expected %d categories, got %d", len(tests), len(categories))
+	}
+	for _, test := range tests {
+		t.Run(test.name, func(t *testing.T) {
+			err := fmt.Errorf("tick: %w", Wrap(test.category, errors.New("failure")))
+			if name := Category(err); name != test.name {
+				t.Errorf("expected the category '%s', got '%s'", test.name, name)
+			}
+			if retryable := Retryable(err); retryable != test.retryable {
+				t.Errorf("expected retryable to be %v, got %v", test.retryable, retryable)
+			}
+			if quarantine := Quarantine(err); quarantine != test.quarantine {
+				t.Errorf("expected quarantine to be %v, got %v", test.quarantine, quarantine)
+			}
+		})
+	}
+
+	plain := errors.New("failure")
+	if name := Category(plain); name != UnknownCategory {
+		t.Errorf("expected the category '%s' for an uncategorized error, got '%s'", UnknownCategory, name)
+	}
+	if Retryable(plain) || Quarantine(plain) {
+		t.Errorf("an uncategorized error has been classified")
+	}
+}